@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// supported authentication types of the datasource.
+// If no type is configured, basic auth is used if username and password are set.
+const (
+	AUTH_TYPE_NONE     string = "none"
+	AUTH_TYPE_BASIC    string = "basic"
+	AUTH_TYPE_BEARER   string = "bearer"
+	AUTH_TYPE_KERBEROS string = "kerberos"
+	AUTH_TYPE_CUSTOM   string = "custom"
+)
+
+// creates the auth token of the driver according to the auth type
+func (s neo4JSettings) authToken() (neo4j.AuthToken, error) {
+	switch s.AuthType {
+	case "":
+		if s.Username != "" && s.Password != "" {
+			return neo4j.BasicAuth(s.Username, s.Password, s.AuthRealm), nil
+		}
+		return neo4j.NoAuth(), nil
+	case AUTH_TYPE_NONE:
+		return neo4j.NoAuth(), nil
+	case AUTH_TYPE_BASIC:
+		if s.Username == "" || s.Password == "" {
+			return neo4j.AuthToken{}, errors.New("Invalid setting authType: basic auth requires username and password")
+		}
+		return neo4j.BasicAuth(s.Username, s.Password, s.AuthRealm), nil
+	case AUTH_TYPE_BEARER:
+		if s.BearerToken == "" {
+			return neo4j.AuthToken{}, errors.New("Invalid setting authType: bearer auth requires a token")
+		}
+		return neo4j.BearerAuth(s.BearerToken), nil
+	case AUTH_TYPE_KERBEROS:
+		if s.KerberosTicket == "" {
+			return neo4j.AuthToken{}, errors.New("Invalid setting authType: kerberos auth requires a ticket")
+		}
+		return neo4j.KerberosAuth(s.KerberosTicket), nil
+	case AUTH_TYPE_CUSTOM:
+		if s.AuthScheme == "" {
+			return neo4j.AuthToken{}, errors.New("Invalid setting authScheme: custom auth requires a scheme")
+		}
+		parameters, err := toAuthParameters(s.AuthParameters)
+		if err != nil {
+			return neo4j.AuthToken{}, err
+		}
+		return neo4j.CustomAuth(s.AuthScheme, s.Username, s.Password, s.AuthRealm, parameters), nil
+	default:
+		return neo4j.AuthToken{}, fmt.Errorf("Invalid setting authType: unknown type '%s'", s.AuthType)
+	}
+}
+
+// parameters of the custom auth are configured as json object
+func toAuthParameters(value string) (map[string]any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+
+	var parameters map[string]any
+	err := decoder.Decode(&parameters)
+	if err != nil {
+		return nil, errors.New("Invalid setting authParameters: must be a json object")
+	}
+
+	for k, v := range parameters {
+		parameters[k] = fromJsonValue(v)
+	}
+	return parameters, nil
+}
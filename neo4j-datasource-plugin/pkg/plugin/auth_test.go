@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestAuthToken(t *testing.T) {
+	tests := []struct {
+		settings neo4JSettings
+		expected neo4j.AuthToken
+	}{
+		{neo4JSettings{}, neo4j.NoAuth()},
+		{neo4JSettings{Username: "neo4j"}, neo4j.NoAuth()},
+		{neo4JSettings{Username: "neo4j", Password: "secret"}, neo4j.BasicAuth("neo4j", "secret", "")},
+		{neo4JSettings{AuthType: "none", Username: "neo4j", Password: "secret"}, neo4j.NoAuth()},
+		{neo4JSettings{AuthType: "bearer", BearerToken: "token"}, neo4j.BearerAuth("token")},
+		{neo4JSettings{AuthType: "kerberos", KerberosTicket: "ticket"}, neo4j.KerberosAuth("ticket")},
+		{
+			neo4JSettings{AuthType: "custom", AuthScheme: "sso", Username: "alice", Password: "secret", AuthRealm: "corp", AuthParameters: `{"tenant": 7, "roles": ["reader"]}`},
+			neo4j.CustomAuth("sso", "alice", "secret", "corp", map[string]any{"tenant": int64(7), "roles": []any{"reader"}}),
+		},
+	}
+
+	for _, test := range tests {
+		token, err := test.settings.authToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diff := cmp.Diff(token.Tokens, test.expected.Tokens)
+		if diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestInvalidAuthSettings(t *testing.T) {
+	tests := []struct {
+		settings        neo4JSettings
+		expectedMessage string
+	}{
+		{neo4JSettings{AuthType: "basic"}, "requires username and password"},
+		{neo4JSettings{AuthType: "bearer"}, "requires a token"},
+		{neo4JSettings{AuthType: "kerberos"}, "requires a ticket"},
+		{neo4JSettings{AuthType: "custom"}, "Invalid setting authScheme"},
+		{neo4JSettings{AuthType: "custom", AuthScheme: "sso", AuthParameters: "[]"}, "Invalid setting authParameters"},
+		{neo4JSettings{AuthType: "ldap"}, "unknown type 'ldap'"},
+	}
+
+	for _, test := range tests {
+		err := test.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error '%s', but was %v", test.expectedMessage, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+// builds the configuration of the driver from the settings.
+// Settings which are not configured keep the defaults of the driver.
+func (s neo4JSettings) driverConfig(datasourceId string) (func(*config.Config), error) {
+	if s.MaxConnectionPoolSize < 0 {
+		return nil, errors.New("Invalid setting maxConnectionPoolSize: must not be negative")
+	}
+
+	if s.FetchSize < neo4j.FetchAll {
+		return nil, errors.New("Invalid setting fetchSize: must be -1 to fetch all records, 0 for the default or positive")
+	}
+
+	maxConnectionLifetime, err := parseDurationSetting("maxConnectionLifetime", s.MaxConnectionLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionAcquisitionTimeout, err := parseDurationSetting("connectionAcquisitionTimeout", s.ConnectionAcquisitionTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionLivenessCheckTimeout, err := parseDurationSetting("connectionLivenessCheckTimeout", s.ConnectionLivenessCheckTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	socketConnectTimeout, err := parseDurationSetting("socketConnectTimeout", s.SocketConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetryTime, err := parseDurationSetting("maxRetryTime", s.MaxRetryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel, err := s.driverLogLevel()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *config.Config) {
+		c.TlsConfig = tlsConfig
+		c.FetchSize = s.FetchSize
+
+		if logLevel > 0 {
+			c.Log = &driverLogger{datasourceId: datasourceId, level: logLevel}
+		}
+
+		if s.MaxConnectionPoolSize > 0 {
+			c.MaxConnectionPoolSize = s.MaxConnectionPoolSize
+		}
+		if maxConnectionLifetime > 0 {
+			c.MaxConnectionLifetime = maxConnectionLifetime
+		}
+		if connectionAcquisitionTimeout > 0 {
+			c.ConnectionAcquisitionTimeout = connectionAcquisitionTimeout
+		}
+		if connectionLivenessCheckTimeout > 0 {
+			c.ConnectionLivenessCheckTimeout = connectionLivenessCheckTimeout
+		}
+		if socketConnectTimeout > 0 {
+			c.SocketConnectTimeout = socketConnectTimeout
+		}
+		if maxRetryTime > 0 {
+			c.MaxTransactionRetryTime = maxRetryTime
+		}
+		if s.SocketKeepalive != nil {
+			c.SocketKeepalive = *s.SocketKeepalive
+		}
+	}, nil
+}
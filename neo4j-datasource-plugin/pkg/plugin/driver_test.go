@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+func TestDriverConfig(t *testing.T) {
+	keepalive := false
+	settings := neo4JSettings{
+		MaxConnectionPoolSize:        200,
+		MaxConnectionLifetime:        "5m",
+		ConnectionAcquisitionTimeout: "10s",
+		MaxRetryTime:                 "1m",
+		SocketKeepalive:              &keepalive,
+		FetchSize:                    -1,
+	}
+
+	configure, err := settings.driverConfig("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := config.Config{SocketKeepalive: true, SocketConnectTimeout: 5 * time.Second}
+	configure(&c)
+
+	if c.MaxConnectionPoolSize != 200 || c.MaxConnectionLifetime != 5*time.Minute || c.ConnectionAcquisitionTimeout != 10*time.Second {
+		t.Error("Expected configured pool settings, but was", c.MaxConnectionPoolSize, c.MaxConnectionLifetime, c.ConnectionAcquisitionTimeout)
+	}
+
+	if c.MaxTransactionRetryTime != time.Minute {
+		t.Error("Expected max retry time 1m, but was", c.MaxTransactionRetryTime)
+	}
+
+	if c.SocketKeepalive || c.FetchSize != -1 {
+		t.Error("Expected keepalive off and fetch all")
+	}
+
+	if c.SocketConnectTimeout != 5*time.Second {
+		t.Error("Expected default of driver for socket connect timeout, but was", c.SocketConnectTimeout)
+	}
+}
+
+func TestInvalidDriverSettings(t *testing.T) {
+	tests := []struct {
+		settings        neo4JSettings
+		expectedMessage string
+	}{
+		{neo4JSettings{MaxConnectionPoolSize: -1}, "Invalid setting maxConnectionPoolSize"},
+		{neo4JSettings{FetchSize: -2}, "Invalid setting fetchSize"},
+		{neo4JSettings{MaxConnectionLifetime: "long"}, "Invalid setting maxConnectionLifetime"},
+		{neo4JSettings{SocketConnectTimeout: "-1s"}, "Invalid setting socketConnectTimeout"},
+		{neo4JSettings{MaxRetryTime: "often"}, "Invalid setting maxRetryTime"},
+	}
+
+	for _, test := range tests {
+		err := test.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error '%s', but was %v", test.expectedMessage, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// error of a query with a message for the user, a status and a source, which tells
+// whether the plugin failed or the query or database. The source is set as ErrorSource of the query response.
+type queryError struct {
+	message string
+	status  backend.Status
+	source  backend.ErrorSource
+	err     error
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+// error of the query, which is caused by an invalid query or invalid settings of the query
+func newValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &queryError{message: err.Error(), status: backend.StatusBadRequest, source: backend.ErrorSourceDownstream, err: err}
+}
+
+// maps the error to a queryError with an actionable message.
+// Errors which are not known are errors of the plugin.
+func classifyError(err error) *queryError {
+	var qErr *queryError
+	if errors.As(err, &qErr) {
+		return qErr
+	}
+
+	var neo4jErr *neo4j.Neo4jError
+	if errors.As(err, &neo4jErr) {
+		return classifyNeo4jError(neo4jErr)
+	}
+
+	var connectivityErr *neo4j.ConnectivityError
+	if errors.As(err, &connectivityErr) {
+		message := "ConnectivityError: Can not connect to specified url. Please review log for more details."
+		if tlsMessage, isTlsError := describeTlsError(err); isTlsError {
+			message = "ConnectivityError: " + tlsMessage
+		}
+		return &queryError{message: message, status: backend.StatusBadGateway, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	var tokenExpiredErr *neo4j.TokenExpiredError
+	if errors.As(err, &tokenExpiredErr) {
+		return &queryError{message: "Authentication failed: the token expired. " + tokenExpiredErr.Message, status: backend.StatusUnauthorized, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	var executionLimitErr *neo4j.TransactionExecutionLimit
+	if errors.As(err, &executionLimitErr) {
+		return &queryError{message: "Query failed after retries: " + executionLimitErr.Error(), status: http.StatusServiceUnavailable, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &queryError{message: "Query timed out. Increase the query timeout or refine the query.", status: backend.StatusTimeout, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return &queryError{message: "Query was cancelled", status: backend.StatusBadRequest, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	return &queryError{message: err.Error(), status: backend.StatusInternal, source: backend.ErrorSourcePlugin, err: err}
+}
+
+// https://neo4j.com/docs/status-codes/current/errors/all-errors/
+func classifyNeo4jError(err *neo4j.Neo4jError) *queryError {
+	downstream := func(status backend.Status, message string) *queryError {
+		return &queryError{message: message + ": " + err.Msg, status: status, source: backend.ErrorSourceDownstream, err: err}
+	}
+
+	switch err.Code {
+	case "Neo.ClientError.Statement.SyntaxError", "Neo.ClientError.Statement.SemanticError",
+		"Neo.ClientError.Statement.ParameterMissing", "Neo.ClientError.Statement.TypeError",
+		"Neo.ClientError.Statement.ArgumentError", "Neo.ClientError.Statement.ArithmeticError":
+		return downstream(backend.StatusBadRequest, "Invalid Cypher query")
+	case "Neo.ClientError.Procedure.ProcedureNotFound", "Neo.ClientError.Statement.UnknownFunction":
+		return downstream(backend.StatusBadRequest, "Unknown procedure or function")
+	case "Neo.ClientError.Security.Unauthorized", "Neo.ClientError.Security.AuthenticationRateLimit",
+		"Neo.ClientError.Security.CredentialsExpired", "Neo.ClientError.Security.TokenExpired":
+		return downstream(backend.StatusUnauthorized, "Authentication failed")
+	case "Neo.ClientError.Database.DatabaseNotFound":
+		return downstream(backend.StatusNotFound, "Database not found")
+	case "Neo.ClientError.Transaction.TransactionTimedOut", "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration":
+		return downstream(backend.StatusTimeout, "Query timed out. Increase the query timeout or refine the query")
+	case "Neo.TransientError.General.TransactionMemoryLimit", "Neo.ClientError.General.TransactionOutOfMemoryError":
+		return downstream(backend.StatusBadRequest, "Query exceeded the memory limit of the transaction. Refine the query or reduce the result")
+	case "Neo.TransientError.General.MemoryPoolOutOfMemoryError", "Neo.TransientError.General.OutOfMemoryError":
+		return downstream(http.StatusServiceUnavailable, "Database is out of memory, retry the query later")
+	}
+
+	switch {
+	case err.HasSecurityCode():
+		return downstream(backend.StatusForbidden, "Access denied")
+	case err.Classification() == "TransientError" || strings.HasPrefix(err.Code, "Neo.ClientError.Cluster."):
+		return downstream(http.StatusServiceUnavailable, "Temporary error of the database, retry the query")
+	case err.Classification() == "ClientError":
+		return downstream(backend.StatusBadRequest, "Query failed")
+	default:
+		return downstream(backend.StatusInternal, "Database error")
+	}
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err             error
+		expectedStatus  backend.Status
+		expectedSource  backend.ErrorSource
+		expectedMessage string
+	}{
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError", Msg: "Invalid input 'RETRUN'"}, backend.StatusBadRequest, backend.ErrorSourceDownstream, "Invalid Cypher query: Invalid input 'RETRUN'"},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized", Msg: "unauthorized"}, backend.StatusUnauthorized, backend.ErrorSourceDownstream, "Authentication failed"},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Security.Forbidden", Msg: "denied"}, backend.StatusForbidden, backend.ErrorSourceDownstream, "Access denied"},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Database.DatabaseNotFound", Msg: "this database does not exist"}, backend.StatusNotFound, backend.ErrorSourceDownstream, "Database not found: this database does not exist"},
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.General.TransactionMemoryLimit", Msg: "limit"}, backend.StatusBadRequest, backend.ErrorSourceDownstream, "memory limit"},
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.Cluster.NotALeader", Msg: "no leader"}, http.StatusServiceUnavailable, backend.ErrorSourceDownstream, "retry the query"},
+		{&neo4j.Neo4jError{Code: "Neo.DatabaseError.General.UnknownError", Msg: "boom"}, backend.StatusInternal, backend.ErrorSourceDownstream, "Database error: boom"},
+		{&neo4j.ConnectivityError{Inner: errors.New("refused")}, backend.StatusBadGateway, backend.ErrorSourceDownstream, "ConnectivityError: Can not connect"},
+		{fmt.Errorf("run: %w", context.DeadlineExceeded), backend.StatusTimeout, backend.ErrorSourceDownstream, "Query timed out"},
+		{newValidationError(errors.New("Unknown macro")), backend.StatusBadRequest, backend.ErrorSourceDownstream, "Unknown macro"},
+		{errors.New("unexpected"), backend.StatusInternal, backend.ErrorSourcePlugin, "unexpected"},
+	}
+
+	for _, test := range tests {
+		qErr := classifyError(test.err)
+
+		if qErr.status != test.expectedStatus || qErr.source != test.expectedSource {
+			t.Errorf("Expected status %d and source %s for %v, but was %d and %s", test.expectedStatus, test.expectedSource, test.err, qErr.status, qErr.source)
+		}
+
+		if !strings.Contains(qErr.Error(), test.expectedMessage) {
+			t.Errorf("Expected message containing '%s', but was '%s'", test.expectedMessage, qErr.Error())
+		}
+
+		if !errors.Is(qErr, test.err) && !errors.Is(test.err, qErr) {
+			t.Errorf("Expected cause %v to be kept", test.err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// initial capacity of the field vectors, if no row limit is given
+const DEFAULT_FIELD_CAPACITY int = 64
+
+// limits the number of records read from a result
+// and remembers whether the result contained more records than the limit.
+type rowLimit struct {
+	// max <= 0 means unlimited
+	max      int
+	exceeded bool
+}
+
+func newRowLimit(max int) *rowLimit {
+	return &rowLimit{max: max}
+}
+
+func (l *rowLimit) truncated() bool {
+	return l.exceeded
+}
+
+// creates a notice for the query inspector and panel, which tells that records were dropped.
+// The remaining records are not read, therefore their number is unknown.
+func (l *rowLimit) notice() data.Notice {
+	return data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Result was truncated to %d records, because the query returned more than %d records. Increase the row limit or refine the query.", l.max, l.max),
+	}
+}
+
+// streams the records of the result one by one to the given function, until the row limit
+// is reached. If more records follow, they are discarded with Consume without reading them.
+func forEachRecord(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit, fn func(record *neo4j.Record)) error {
+	rows := 0
+	for limit.max <= 0 || rows < limit.max {
+		if !result.Next(ctx) {
+			return result.Err()
+		}
+		fn(result.Record())
+		rows++
+	}
+
+	if !result.Peek(ctx) {
+		return result.Err()
+	}
+
+	limit.exceeded = true
+	_, err := result.Consume(ctx)
+	return err
+}
+
+// builds a table frame row by row.
+// The type of a column is inferred from its first non nil value. Until then nil values
+// are only counted and the field is created when the type is known.
+type tableFrameBuilder struct {
+	name         string
+	keys         []string
+	fields       []*data.Field
+	pendingNulls []int
+	capacity     int
+	rows         int
+}
+
+func newTableFrameBuilder(name string, keys []string, limit *rowLimit) *tableFrameBuilder {
+	capacity := DEFAULT_FIELD_CAPACITY
+	if limit.max > 0 && limit.max < capacity {
+		capacity = limit.max
+	}
+
+	return &tableFrameBuilder{
+		name:         name,
+		keys:         keys,
+		fields:       make([]*data.Field, len(keys)),
+		pendingNulls: make([]int, len(keys)),
+		capacity:     capacity,
+	}
+}
+
+func (b *tableFrameBuilder) append(record *neo4j.Record) {
+	for col, v := range record.Values {
+		field := b.fields[col]
+		if field == nil {
+			typ := getTypeArrayByVal(v, b.capacity)
+			if typ == nil {
+				b.pendingNulls[col]++
+				continue
+			}
+
+			field = data.NewField(b.keys[col], nil, typ)
+			field.Extend(b.pendingNulls[col])
+			b.fields[col] = field
+		}
+		field.Append(toValue(v))
+	}
+	b.rows++
+}
+
+func (b *tableFrameBuilder) build() *data.Frame {
+	frame := data.NewFrame(b.name)
+	for col, field := range b.fields {
+		if field == nil {
+			log.DefaultLogger.Debug("After looking at all rows, type is still nil. Assigning string-type as default")
+			field = data.NewField(b.keys[col], nil, make([]*string, b.pendingNulls[col]))
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame
+}
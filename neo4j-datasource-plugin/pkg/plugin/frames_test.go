@@ -0,0 +1,153 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestTableFrameBuilderInfersTypeAfterNulls(t *testing.T) {
+	keys := []string{"A", "B", "C"}
+	builder := newTableFrameBuilder("response", keys, newRowLimit(0))
+
+	builder.append(&neo4j.Record{Keys: keys, Values: []any{nil, "one", nil}})
+	builder.append(&neo4j.Record{Keys: keys, Values: []any{nil, "two", nil}})
+	builder.append(&neo4j.Record{Keys: keys, Values: []any{int64(3), nil, nil}})
+
+	expected := data.NewFrame("response",
+		data.NewField("A", nil, []*int64{nil, nil, ptrI(3)}),
+		data.NewField("B", nil, []*string{ptrS("one"), ptrS("two"), nil}),
+		data.NewField("C", nil, []*string{nil, nil, nil}),
+	)
+
+	diff := cmp.Diff(builder.build(), expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestTableFrameBuilderWithoutRows(t *testing.T) {
+	builder := newTableFrameBuilder("response", []string{"m"}, newRowLimit(10))
+
+	expected := data.NewFrame("response",
+		data.NewField("m", nil, []*string{}),
+	)
+
+	diff := cmp.Diff(builder.build(), expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestRowLimitNotice(t *testing.T) {
+	limit := newRowLimit(3)
+	if limit.truncated() {
+		t.Fatal("Expected not truncated without more records")
+	}
+
+	limit.exceeded = true
+	if !limit.truncated() {
+		t.Fatal("Expected truncated with more records")
+	}
+
+	notice := limit.notice()
+	if notice.Severity != data.NoticeSeverityWarning {
+		t.Error("Expected severity warning, but was " + notice.Severity.String())
+	}
+
+	expectedText := "Result was truncated to 3 records, because the query returned more than 3 records. Increase the row limit or refine the query."
+	if notice.Text != expectedText {
+		t.Error("Expected notice " + expectedText + ", but was " + notice.Text)
+	}
+}
+
+// result with the given number of records, which counts the records read
+type countingResult struct {
+	neo4j.ResultWithContext
+	records  int
+	read     int
+	consumed bool
+}
+
+func (r *countingResult) Next(ctx context.Context) bool {
+	if r.consumed || r.read >= r.records {
+		return false
+	}
+	r.read++
+	return true
+}
+
+func (r *countingResult) Peek(ctx context.Context) bool {
+	return !r.consumed && r.read < r.records
+}
+
+func (r *countingResult) Record() *neo4j.Record {
+	return &neo4j.Record{Keys: []string{"i"}, Values: []any{int64(r.read)}}
+}
+
+func (r *countingResult) Err() error {
+	return nil
+}
+
+func (r *countingResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
+	r.consumed = true
+	return nil, nil
+}
+
+func TestForEachRecordStopsAtLimit(t *testing.T) {
+	result := &countingResult{records: 1000000}
+	limit := newRowLimit(3)
+
+	rows := 0
+	err := forEachRecord(context.Background(), result, limit, func(record *neo4j.Record) { rows++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rows != 3 || result.read != 3 {
+		t.Errorf("Expected 3 records to be read, but were %d of %d", rows, result.read)
+	}
+
+	if !result.consumed || !limit.truncated() {
+		t.Error("Expected the remaining records to be discarded with consume and the result to be truncated")
+	}
+}
+
+func TestForEachRecordWithinLimit(t *testing.T) {
+	result := &countingResult{records: 3}
+	limit := newRowLimit(3)
+
+	err := forEachRecord(context.Background(), result, limit, func(record *neo4j.Record) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.consumed || limit.truncated() {
+		t.Error("Expected not truncated, because the result has no more records than the limit")
+	}
+}
+
+func TestRowLimitInCypher(t *testing.T) {
+	skipIfIsShort(t)
+	expectedFrame := data.NewFrame("response",
+		data.NewField("i", nil, []*int64{ptrI(1), ptrI(2), ptrI(3)}),
+	)
+	expectedFrame.SetMeta(&data.FrameMeta{
+		ExecutedQueryString: "UNWIND range(1, 10) as i return i",
+		Notices: []data.Notice{{
+			Severity: data.NoticeSeverityWarning,
+			Text:     "Result was truncated to 3 records, because the query returned more than 3 records. Increase the row limit or refine the query.",
+		}},
+	})
+
+	query := neo4JQuery{
+		CypherQuery: "UNWIND range(1, 10) as i return i",
+		Format:      "table",
+		MaxRows:     3,
+	}
+
+	runNeo4JIntegrationTableQueryTest(t, query, expectedFrame)
+}
@@ -0,0 +1,359 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// sources of the node graph fields besides properties
+const (
+	GRAPH_SOURCE_LABEL  string = "$label"
+	GRAPH_SOURCE_LABELS string = "$labels"
+	GRAPH_SOURCE_TYPE   string = "$type"
+)
+
+// maps properties and labels of nodes and relationships onto the fields of the node graph.
+// Each source is the name of a property or one of GRAPH_SOURCE_*, empty keeps the default.
+// https://grafana.com/docs/grafana/latest/panels-visualizations/visualizations/node-graph/#data-api
+type nodeGraphOptions struct {
+	NodeTitle         string               `json:"nodeTitle"`
+	NodeSubTitle      string               `json:"nodeSubTitle"`
+	NodeMainStat      string               `json:"nodeMainStat"`
+	NodeSecondaryStat string               `json:"nodeSecondaryStat"`
+	NodeColor         string               `json:"nodeColor"`
+	NodeRadius        string               `json:"nodeRadius"`
+	NodeArcs          []nodeGraphArc       `json:"nodeArcs"`
+	LabelRules        []nodeGraphLabelRule `json:"labelRules"`
+	EdgeMainStat      string               `json:"edgeMainStat"`
+	EdgeSecondaryStat string               `json:"edgeSecondaryStat"`
+}
+
+// a numeric property between 0 and 1, which is shown as colored section of the circle around the node
+type nodeGraphArc struct {
+	Property string `json:"property"`
+	Color    string `json:"color"`
+}
+
+// sets the color and icon of nodes with the label, the first matching rule wins
+type nodeGraphLabelRule struct {
+	Label string `json:"label"`
+	Color string `json:"color"`
+	Icon  string `json:"icon"`
+}
+
+// collects the distinct nodes and relationships of the records for the node graph.
+// Paths, lists and maps are walked recursively, e.g. for RETURN p, collect(n) or nodes(p).
+// A single record like RETURN collect(p) can contain the whole graph, therefore the number of
+// nodes and relationships is limited as well.
+type graphCollector struct {
+	// max <= 0 means unlimited
+	max       int
+	truncated bool
+	nodeIds   map[string]bool
+	edgeIds   map[string]bool
+	nodes     []dbtype.Node
+	edges     []dbtype.Relationship
+}
+
+func newGraphCollector(max int) *graphCollector {
+	return &graphCollector{
+		max:     max,
+		nodeIds: make(map[string]bool),
+		edgeIds: make(map[string]bool),
+	}
+}
+
+func (c *graphCollector) add(value any) {
+	switch t := value.(type) {
+	case dbtype.Node:
+		if !c.nodeIds[t.ElementId] {
+			if c.max > 0 && len(c.nodes) >= c.max {
+				c.truncated = true
+				return
+			}
+			c.nodeIds[t.ElementId] = true
+			c.nodes = append(c.nodes, t)
+		}
+	case dbtype.Relationship:
+		if !c.edgeIds[t.ElementId] {
+			if c.max > 0 && len(c.edges) >= c.max {
+				c.truncated = true
+				return
+			}
+			c.edgeIds[t.ElementId] = true
+			c.edges = append(c.edges, t)
+		}
+	case dbtype.Path:
+		for _, node := range t.Nodes {
+			c.add(node)
+		}
+		for _, relationship := range t.Relationships {
+			c.add(relationship)
+		}
+	case []any:
+		for _, v := range t {
+			c.add(v)
+		}
+	case map[string]any:
+		// sorted keys keep the order of the nodes and edges stable
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			c.add(t[key])
+		}
+	}
+}
+
+// creates a notice for the query inspector and panel, which tells that nodes or relationships were dropped
+func (c *graphCollector) notice() data.Notice {
+	return data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Node graph was truncated to %d nodes and %d relationships, because the result contains more. Increase the row limit or refine the query.", c.max, c.max),
+	}
+}
+
+func (c *graphCollector) nodeProps() []map[string]any {
+	props := make([]map[string]any, len(c.nodes))
+	for i, node := range c.nodes {
+		props[i] = node.Props
+	}
+	return props
+}
+
+func (c *graphCollector) edgeProps() []map[string]any {
+	props := make([]map[string]any, len(c.edges))
+	for i, edge := range c.edges {
+		props[i] = edge.Props
+	}
+	return props
+}
+
+func (o nodeGraphOptions) validate() error {
+	nodeSources := map[string]string{
+		"nodeTitle":         o.NodeTitle,
+		"nodeSubTitle":      o.NodeSubTitle,
+		"nodeMainStat":      o.NodeMainStat,
+		"nodeSecondaryStat": o.NodeSecondaryStat,
+		"nodeColor":         o.NodeColor,
+		"nodeRadius":        o.NodeRadius,
+	}
+	for name, source := range nodeSources {
+		if strings.HasPrefix(source, "$") && source != GRAPH_SOURCE_LABEL && source != GRAPH_SOURCE_LABELS {
+			return fmt.Errorf("Invalid node graph option %s: unknown source '%s', use a property, %s or %s", name, source, GRAPH_SOURCE_LABEL, GRAPH_SOURCE_LABELS)
+		}
+	}
+
+	edgeSources := map[string]string{
+		"edgeMainStat":      o.EdgeMainStat,
+		"edgeSecondaryStat": o.EdgeSecondaryStat,
+	}
+	for name, source := range edgeSources {
+		if strings.HasPrefix(source, "$") && source != GRAPH_SOURCE_TYPE {
+			return fmt.Errorf("Invalid node graph option %s: unknown source '%s', use a property or %s", name, source, GRAPH_SOURCE_TYPE)
+		}
+	}
+
+	for _, arc := range o.NodeArcs {
+		if arc.Property == "" || arc.Color == "" {
+			return fmt.Errorf("Invalid node graph option nodeArcs: property and color are required")
+		}
+	}
+
+	for _, rule := range o.LabelRules {
+		if rule.Label == "" {
+			return fmt.Errorf("Invalid node graph option labelRules: label is required")
+		}
+	}
+
+	return nil
+}
+
+func nodeValue(node dbtype.Node, source string) any {
+	switch source {
+	case GRAPH_SOURCE_LABEL:
+		if len(node.Labels) == 0 {
+			return nil
+		}
+		return node.Labels[0]
+	case GRAPH_SOURCE_LABELS:
+		return strings.Join(node.Labels, ", ")
+	default:
+		return node.Props[source]
+	}
+}
+
+func edgeValue(edge dbtype.Relationship, source string) any {
+	if source == GRAPH_SOURCE_TYPE {
+		return edge.Type
+	}
+	return edge.Props[source]
+}
+
+// returns the first rule, which matches a label of the node and defines the attribute
+func (o nodeGraphOptions) labelRule(node dbtype.Node, attribute func(nodeGraphLabelRule) string) string {
+	for _, rule := range o.LabelRules {
+		if attribute(rule) == "" {
+			continue
+		}
+		for _, label := range node.Labels {
+			if label == rule.Label {
+				return attribute(rule)
+			}
+		}
+	}
+	return ""
+}
+
+func ruleColor(rule nodeGraphLabelRule) string {
+	return rule.Color
+}
+
+func ruleIcon(rule nodeGraphLabelRule) string {
+	return rule.Icon
+}
+
+// sets the mapped fields of the nodes frame, which has a row per node
+func (o nodeGraphOptions) applyToNodes(frame *data.Frame, nodes []dbtype.Node) {
+	values := func(value func(node dbtype.Node) any) []any {
+		result := make([]any, len(nodes))
+		for i, node := range nodes {
+			result[i] = value(node)
+		}
+		return result
+	}
+
+	sourceValues := func(source string) []any {
+		return values(func(node dbtype.Node) any { return nodeValue(node, source) })
+	}
+
+	if o.NodeTitle != "" {
+		setGraphField(frame, toGraphStringField("title", sourceValues(o.NodeTitle)))
+	}
+	if o.NodeSubTitle != "" {
+		setGraphField(frame, toGraphStringField("subTitle", sourceValues(o.NodeSubTitle)))
+	}
+	if o.NodeMainStat != "" {
+		setGraphField(frame, toGraphStatField("mainStat", sourceValues(o.NodeMainStat)))
+	}
+	if o.NodeSecondaryStat != "" {
+		setGraphField(frame, toGraphStatField("secondaryStat", sourceValues(o.NodeSecondaryStat)))
+	}
+
+	// the color of a label rule overrides the color property
+	if o.NodeColor != "" || o.hasLabelRule(ruleColor) {
+		setGraphField(frame, toGraphStringField("color", values(func(node dbtype.Node) any {
+			if color := o.labelRule(node, ruleColor); color != "" {
+				return color
+			}
+			if o.NodeColor == "" {
+				return nil
+			}
+			return nodeValue(node, o.NodeColor)
+		})))
+	}
+
+	if o.hasLabelRule(ruleIcon) {
+		setGraphField(frame, toGraphStringField("icon", values(func(node dbtype.Node) any {
+			if icon := o.labelRule(node, ruleIcon); icon != "" {
+				return icon
+			}
+			return nil
+		})))
+	}
+
+	if o.NodeRadius != "" {
+		setGraphField(frame, toGraphNumericField("nodeRadius", sourceValues(o.NodeRadius)))
+	}
+
+	for _, arc := range o.NodeArcs {
+		field := toGraphNumericField("arc__"+arc.Property, sourceValues(arc.Property))
+		field.Config = &data.FieldConfig{Color: map[string]interface{}{"mode": "fixed", "fixedColor": arc.Color}}
+		setGraphField(frame, field)
+	}
+}
+
+// sets the mapped fields of the edges frame, which has a row per edge
+func (o nodeGraphOptions) applyToEdges(frame *data.Frame, edges []dbtype.Relationship) {
+	sourceValues := func(source string) []any {
+		result := make([]any, len(edges))
+		for i, edge := range edges {
+			result[i] = edgeValue(edge, source)
+		}
+		return result
+	}
+
+	if o.EdgeMainStat != "" {
+		setGraphField(frame, toGraphStatField("mainStat", sourceValues(o.EdgeMainStat)))
+	}
+	if o.EdgeSecondaryStat != "" {
+		setGraphField(frame, toGraphStatField("secondaryStat", sourceValues(o.EdgeSecondaryStat)))
+	}
+}
+
+func (o nodeGraphOptions) hasLabelRule(attribute func(nodeGraphLabelRule) string) bool {
+	for _, rule := range o.LabelRules {
+		if attribute(rule) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// replaces the field with the same name or appends it
+func setGraphField(frame *data.Frame, field *data.Field) {
+	for i, existing := range frame.Fields {
+		if existing.Name == field.Name {
+			frame.Fields[i] = field
+			return
+		}
+	}
+	frame.Fields = append(frame.Fields, field)
+}
+
+// strings are kept as they are, all other values are converted to json
+func toGraphStringField(name string, values []any) *data.Field {
+	field := data.NewField(name, nil, make([]*string, len(values)))
+	for i, value := range values {
+		switch t := value.(type) {
+		case nil:
+		case string:
+			field.Set(i, ptr(t))
+		default:
+			field.Set(i, asJson(t))
+		}
+	}
+	return field
+}
+
+// values which are not numeric are nil
+func toGraphNumericField(name string, values []any) *data.Field {
+	field := data.NewField(name, nil, make([]*float64, len(values)))
+	for i, value := range values {
+		switch t := value.(type) {
+		case int64:
+			field.Set(i, ptr(float64(t)))
+		case float64:
+			field.Set(i, ptr(t))
+		}
+	}
+	return field
+}
+
+// a stat is numeric, if all values are numbers, otherwise it is shown as text
+func toGraphStatField(name string, values []any) *data.Field {
+	for _, value := range values {
+		switch value.(type) {
+		case nil, int64, float64:
+		default:
+			return toGraphStringField(name, values)
+		}
+	}
+	return toGraphNumericField(name, values)
+}
@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestGraphCollectorWalksPathsListsAndMaps(t *testing.T) {
+	keanu := dbtype.Node{ElementId: "1", Labels: []string{"Person"}}
+	matrix := dbtype.Node{ElementId: "2", Labels: []string{"Movie"}}
+	lana := dbtype.Node{ElementId: "3", Labels: []string{"Person"}}
+	actedIn := dbtype.Relationship{ElementId: "10", StartElementId: "1", EndElementId: "2", Type: "ACTED_IN"}
+	directed := dbtype.Relationship{ElementId: "11", StartElementId: "3", EndElementId: "2", Type: "DIRECTED"}
+
+	graph := newGraphCollector(0)
+	graph.add(dbtype.Path{Nodes: []dbtype.Node{keanu, matrix}, Relationships: []dbtype.Relationship{actedIn}})
+	graph.add([]any{matrix, lana, "Lana", int64(1)})
+	graph.add(map[string]any{"r": directed, "nested": []any{actedIn, keanu}})
+	graph.add("not a graph value")
+
+	expectedNodes := []dbtype.Node{keanu, matrix, lana}
+	if diff := cmp.Diff(expectedNodes, graph.nodes); diff != "" {
+		t.Error(diff)
+	}
+
+	expectedEdges := []dbtype.Relationship{actedIn, directed}
+	if diff := cmp.Diff(expectedEdges, graph.edges); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGraphCollectorStopsAtLimit(t *testing.T) {
+	keanu := dbtype.Node{ElementId: "1", Labels: []string{"Person"}}
+	matrix := dbtype.Node{ElementId: "2", Labels: []string{"Movie"}}
+	lana := dbtype.Node{ElementId: "3", Labels: []string{"Person"}}
+	actedIn := dbtype.Relationship{ElementId: "10", StartElementId: "1", EndElementId: "2", Type: "ACTED_IN"}
+	directed := dbtype.Relationship{ElementId: "11", StartElementId: "3", EndElementId: "2", Type: "DIRECTED"}
+	wrote := dbtype.Relationship{ElementId: "12", StartElementId: "3", EndElementId: "2", Type: "WROTE"}
+
+	// a single collected list, like RETURN collect(p)
+	graph := newGraphCollector(2)
+	graph.add([]any{keanu, matrix, actedIn, directed})
+	if graph.truncated {
+		t.Fatal("graph within the limit must not be truncated")
+	}
+
+	graph.add([]any{keanu, lana, wrote})
+	if !graph.truncated {
+		t.Error("expected the graph to be truncated")
+	}
+
+	if diff := cmp.Diff([]dbtype.Node{keanu, matrix}, graph.nodes); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]dbtype.Relationship{actedIn, directed}, graph.edges); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNodeGraphOptionsApplyToNodes(t *testing.T) {
+	nodes := []dbtype.Node{
+		{ElementId: "1", Labels: []string{"Service", "Critical"}, Props: map[string]any{"name": "api", "rps": int64(120), "errors": 0.25, "ok": 0.75}},
+		{ElementId: "2", Labels: []string{"Database"}, Props: map[string]any{"name": "db", "rps": "n/a", "color": "blue"}},
+	}
+
+	options := nodeGraphOptions{
+		NodeTitle:    "name",
+		NodeSubTitle: GRAPH_SOURCE_LABELS,
+		NodeMainStat: "rps",
+		NodeColor:    "color",
+		NodeRadius:   "rps",
+		NodeArcs:     []nodeGraphArc{{Property: "ok", Color: "green"}},
+		LabelRules:   []nodeGraphLabelRule{{Label: "Critical", Color: "red"}, {Label: "Service", Color: "yellow", Icon: "cloud"}},
+	}
+
+	frame := data.NewFrame("nodes",
+		data.NewField("id", nil, []*string{ptrS("1"), ptrS("2")}),
+		data.NewField("title", nil, []*string{ptrS("Service"), ptrS("Database")}),
+	)
+	options.applyToNodes(frame, nodes)
+
+	arc := data.NewField("arc__ok", nil, []*float64{ptrF(0.75), nil})
+	arc.Config = &data.FieldConfig{Color: map[string]interface{}{"mode": "fixed", "fixedColor": "green"}}
+
+	expected := data.NewFrame("nodes",
+		data.NewField("id", nil, []*string{ptrS("1"), ptrS("2")}),
+		data.NewField("title", nil, []*string{ptrS("api"), ptrS("db")}),
+		data.NewField("subTitle", nil, []*string{ptrS("Service, Critical"), ptrS("Database")}),
+		data.NewField("mainStat", nil, []*string{ptrS("120"), ptrS("n/a")}),
+		data.NewField("color", nil, []*string{ptrS("red"), ptrS("blue")}),
+		data.NewField("icon", nil, []*string{ptrS("cloud"), nil}),
+		data.NewField("nodeRadius", nil, []*float64{ptrF(120), nil}),
+		arc,
+	)
+
+	diff := cmp.Diff(expected, frame, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNodeGraphOptionsApplyToEdges(t *testing.T) {
+	edges := []dbtype.Relationship{
+		{ElementId: "10", Type: "CALLS", Props: map[string]any{"calls": int64(7)}},
+		{ElementId: "11", Type: "READS", Props: map[string]any{}},
+	}
+
+	frame := data.NewFrame("edges",
+		data.NewField("id", nil, []*string{ptrS("10"), ptrS("11")}),
+		data.NewField("mainStat", nil, []*string{ptrS("CALLS"), ptrS("READS")}),
+	)
+	nodeGraphOptions{EdgeMainStat: "calls", EdgeSecondaryStat: GRAPH_SOURCE_TYPE}.applyToEdges(frame, edges)
+
+	expected := data.NewFrame("edges",
+		data.NewField("id", nil, []*string{ptrS("10"), ptrS("11")}),
+		data.NewField("mainStat", nil, []*float64{ptrF(7), nil}),
+		data.NewField("secondaryStat", nil, []*string{ptrS("CALLS"), ptrS("READS")}),
+	)
+
+	diff := cmp.Diff(expected, frame, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestInvalidNodeGraphOptions(t *testing.T) {
+	tests := []struct {
+		options         nodeGraphOptions
+		expectedMessage string
+	}{
+		{nodeGraphOptions{NodeTitle: "$type"}, "nodeTitle"},
+		{nodeGraphOptions{EdgeMainStat: "$label"}, "edgeMainStat"},
+		{nodeGraphOptions{NodeArcs: []nodeGraphArc{{Property: "ok"}}}, "nodeArcs"},
+		{nodeGraphOptions{LabelRules: []nodeGraphLabelRule{{Color: "red"}}}, "labelRules"},
+	}
+
+	for _, test := range tests {
+		err := test.options.validate()
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error containing %s, but was %v", test.expectedMessage, err)
+		}
+	}
+
+	if err := (nodeGraphOptions{NodeTitle: "name", NodeSubTitle: GRAPH_SOURCE_LABEL, EdgeMainStat: GRAPH_SOURCE_TYPE}).validate(); err != nil {
+		t.Error("Expected valid options, but was", err)
+	}
+}
+
+func TestInvalidNodeGraphOptionsAreRejectedBeforeExecution(t *testing.T) {
+	// the driver panics, if a session is opened for the query
+	d := &Neo4JDatasource{driver: panickingDriver{}}
+
+	query := neo4JQuery{
+		CypherQuery: "MATCH (n) RETURN n",
+		Format:      "nodegraph",
+		NodeGraph:   nodeGraphOptions{NodeTitle: "$type"},
+	}
+
+	_, err := d.query(context.Background(), query)
+	if err == nil || classifyError(err).status != backend.StatusBadRequest {
+		t.Error("Expected validation error before execution, but was", err)
+	}
+}
@@ -0,0 +1,124 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// query which is executed by the health check, it does not require access to any data
+const HEALTH_QUERY string = "RETURN 1"
+
+// queries for the details of the health check
+const (
+	COMPONENTS_QUERY   string = "CALL dbms.components() YIELD name, versions, edition RETURN name, versions, edition"
+	DATABASES_QUERY    string = "SHOW DATABASES YIELD name, address, role, currentStatus, requestedStatus, `default` WHERE name = $database OR ($database = '' AND `default`) RETURN name, address, role, currentStatus, requestedStatus"
+	CURRENT_USER_QUERY string = "SHOW CURRENT USER YIELD user, roles RETURN user, roles"
+)
+
+// details of the health check, which are shown as JSONDetails
+type healthDetails struct {
+	Version   string          `json:"version,omitempty"`
+	Edition   string          `json:"edition,omitempty"`
+	Database  string          `json:"database,omitempty"`
+	Members   []databaseState `json:"members,omitempty"`
+	User      string          `json:"user,omitempty"`
+	Roles     []string        `json:"roles,omitempty"`
+	LatencyMs int64           `json:"latencyMs"`
+
+	// details which could not be read, e.g. because of missing privileges
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// state of the database on a member of the cluster
+type databaseState struct {
+	Address         string `json:"address"`
+	Role            string `json:"role"`
+	CurrentStatus   string `json:"currentStatus"`
+	RequestedStatus string `json:"requestedStatus"`
+}
+
+// reads the details of the server. Details which can not be read are reported as warning,
+// because they depend on the version, edition and privileges.
+func (d *Neo4JDatasource) readHealthDetails(ctx context.Context, identity sessionIdentity, details *healthDetails) {
+	records, err := d.readRecords(ctx, identity, d.settings.Database, COMPONENTS_QUERY, nil)
+	if err != nil {
+		details.Warnings = append(details.Warnings, "Version: "+err.Error())
+	}
+	for _, record := range records {
+		if name, _, _ := neo4j.GetRecordValue[string](record, "name"); name != "Neo4j Kernel" {
+			continue
+		}
+		versions, _, _ := neo4j.GetRecordValue[[]any](record, "versions")
+		if len(versions) > 0 {
+			details.Version = fmt.Sprint(versions[0])
+		}
+		details.Edition, _, _ = neo4j.GetRecordValue[string](record, "edition")
+	}
+
+	records, err = d.readRecords(ctx, identity, "system", DATABASES_QUERY, map[string]any{"database": d.settings.Database})
+	if err != nil {
+		details.Warnings = append(details.Warnings, "Database: "+err.Error())
+	}
+	for _, record := range records {
+		details.Database, _, _ = neo4j.GetRecordValue[string](record, "name")
+		state := databaseState{}
+		state.Address, _, _ = neo4j.GetRecordValue[string](record, "address")
+		state.Role, _, _ = neo4j.GetRecordValue[string](record, "role")
+		state.CurrentStatus, _, _ = neo4j.GetRecordValue[string](record, "currentStatus")
+		state.RequestedStatus, _, _ = neo4j.GetRecordValue[string](record, "requestedStatus")
+		details.Members = append(details.Members, state)
+	}
+
+	records, err = d.readRecords(ctx, identity, "system", CURRENT_USER_QUERY, nil)
+	if err != nil {
+		details.Warnings = append(details.Warnings, "User: "+err.Error())
+	}
+	for _, record := range records {
+		details.User, _, _ = neo4j.GetRecordValue[string](record, "user")
+		roles, _, _ := neo4j.GetRecordValue[[]any](record, "roles")
+		for _, role := range roles {
+			details.Roles = append(details.Roles, fmt.Sprint(role))
+		}
+	}
+}
+
+// summary of the details for the message of the health check
+func (h healthDetails) summary() string {
+	var parts []string
+	if h.Version != "" {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("Neo4j %s %s", h.Version, h.Edition)))
+	}
+
+	if h.Database != "" {
+		var states []string
+		for _, member := range h.Members {
+			if member.Role != "" && len(h.Members) > 1 {
+				states = append(states, member.Role+" "+member.CurrentStatus)
+			} else {
+				states = append(states, member.CurrentStatus)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("database %s %s", h.Database, strings.Join(states, ", ")))
+	}
+
+	parts = append(parts, fmt.Sprintf("latency %dms", h.LatencyMs))
+	return strings.Join(parts, ", ")
+}
+
+// executes the cypher query and returns all records
+func (d *Neo4JDatasource) readRecords(ctx context.Context, identity sessionIdentity, database string, cypher string, parameters map[string]any) ([]*neo4j.Record, error) {
+	session, err := d.newDatabaseSession(ctx, identity, database)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, cypher, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return result.Collect(ctx)
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func TestHealthDetailsSummary(t *testing.T) {
+	tests := []struct {
+		details  healthDetails
+		expected string
+	}{
+		{healthDetails{LatencyMs: 3}, "latency 3ms"},
+		{
+			healthDetails{Version: "5.12.0", Edition: "enterprise", Database: "neo4j", Members: []databaseState{{Role: "primary", CurrentStatus: "online"}}, LatencyMs: 3},
+			"Neo4j 5.12.0 enterprise, database neo4j online, latency 3ms",
+		},
+		{
+			healthDetails{Database: "neo4j", Members: []databaseState{{Role: "primary", CurrentStatus: "online"}, {Role: "secondary", CurrentStatus: "offline"}}, LatencyMs: 12},
+			"database neo4j primary online, secondary offline, latency 12ms",
+		},
+	}
+
+	for _, test := range tests {
+		summary := test.details.summary()
+		if summary != test.expected {
+			t.Errorf("Expected summary '%s', but was '%s'", test.expected, summary)
+		}
+	}
+}
+
+func TestHealthcheckDetails(t *testing.T) {
+	skipIfIsShort(t)
+
+	settings := backend.DataSourceInstanceSettings{}
+	settings.JSONData = asJsonBytes(t, neo4JSettings{
+		Url:      "neo4j://localhost:7687",
+		Username: "neo4j",
+		Password: "Password123",
+	})
+
+	instance, err := NewNeo4JDatasource(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	neo4JDatasource := instance.(*Neo4JDatasource)
+	defer neo4JDatasource.Dispose()
+
+	res, err := neo4JDatasource.checkHealth(context.Background(), sessionIdentity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details healthDetails
+	err = json.Unmarshal(res.JSONDetails, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if details.Version == "" || details.Database != "neo4j" || details.User != "neo4j" {
+		t.Error("Expected version, database and user in details, but was", string(res.JSONDetails))
+	}
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	neo4jlog "github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// log levels of the driver. Trace additionally logs all bolt messages.
+const (
+	DRIVER_LOG_LEVEL_OFF     string = "off"
+	DRIVER_LOG_LEVEL_ERROR   string = "error"
+	DRIVER_LOG_LEVEL_WARNING string = "warning"
+	DRIVER_LOG_LEVEL_INFO    string = "info"
+	DRIVER_LOG_LEVEL_DEBUG   string = "debug"
+	DRIVER_LOG_LEVEL_TRACE   string = "trace"
+)
+
+// used if no log level of the driver is configured
+const DEFAULT_DRIVER_LOG_LEVEL string = DRIVER_LOG_LEVEL_WARNING
+
+// returns the log level of the driver, 0 means logging is off
+func (s neo4JSettings) driverLogLevel() (neo4jlog.Level, error) {
+	switch s.DriverLogLevel {
+	case DRIVER_LOG_LEVEL_OFF:
+		return 0, nil
+	case DRIVER_LOG_LEVEL_ERROR:
+		return neo4jlog.ERROR, nil
+	case DRIVER_LOG_LEVEL_WARNING, "":
+		return neo4jlog.WARNING, nil
+	case DRIVER_LOG_LEVEL_INFO:
+		return neo4jlog.INFO, nil
+	case DRIVER_LOG_LEVEL_DEBUG, DRIVER_LOG_LEVEL_TRACE:
+		return neo4jlog.DEBUG, nil
+	default:
+		return 0, fmt.Errorf("Invalid setting driverLogLevel: unknown level '%s'", s.DriverLogLevel)
+	}
+}
+
+// writes the log of the driver to the plugin logger of grafana
+type driverLogger struct {
+	datasourceId string
+	level        neo4jlog.Level
+}
+
+func (l *driverLogger) Error(name string, id string, err error) {
+	if l.level >= neo4jlog.ERROR {
+		log.DefaultLogger.Error("Neo4j driver: "+err.Error(), DATASOURCE_UID, l.datasourceId, "component", name, "componentId", id)
+	}
+}
+
+func (l *driverLogger) Warnf(name string, id string, msg string, args ...any) {
+	if l.level >= neo4jlog.WARNING {
+		log.DefaultLogger.Warn("Neo4j driver: "+fmt.Sprintf(msg, args...), DATASOURCE_UID, l.datasourceId, "component", name, "componentId", id)
+	}
+}
+
+func (l *driverLogger) Infof(name string, id string, msg string, args ...any) {
+	if l.level >= neo4jlog.INFO {
+		log.DefaultLogger.Info("Neo4j driver: "+fmt.Sprintf(msg, args...), DATASOURCE_UID, l.datasourceId, "component", name, "componentId", id)
+	}
+}
+
+func (l *driverLogger) Debugf(name string, id string, msg string, args ...any) {
+	if l.level >= neo4jlog.DEBUG {
+		log.DefaultLogger.Debug("Neo4j driver: "+fmt.Sprintf(msg, args...), DATASOURCE_UID, l.datasourceId, "component", name, "componentId", id)
+	}
+}
+
+// writes the bolt messages of a session to the plugin logger of grafana.
+// The driver redacts credentials of the messages.
+type boltLogger struct {
+	datasourceId string
+}
+
+func (l *boltLogger) LogClientMessage(context string, msg string, args ...any) {
+	log.DefaultLogger.Debug("Neo4j bolt C: "+fmt.Sprintf(msg, args...), DATASOURCE_UID, l.datasourceId, "connection", context)
+}
+
+func (l *boltLogger) LogServerMessage(context string, msg string, args ...any) {
+	log.DefaultLogger.Debug("Neo4j bolt S: "+fmt.Sprintf(msg, args...), DATASOURCE_UID, l.datasourceId, "connection", context)
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	neo4jlog "github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+func TestDriverLogLevel(t *testing.T) {
+	tests := []struct {
+		setting  string
+		expected neo4jlog.Level
+	}{
+		{"", neo4jlog.WARNING},
+		{"off", 0},
+		{"error", neo4jlog.ERROR},
+		{"info", neo4jlog.INFO},
+		{"trace", neo4jlog.DEBUG},
+	}
+
+	for _, test := range tests {
+		level, err := neo4JSettings{DriverLogLevel: test.setting}.driverLogLevel()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if level != test.expected {
+			t.Errorf("Expected level %d for '%s', but was %d", test.expected, test.setting, level)
+		}
+	}
+
+	err := neo4JSettings{DriverLogLevel: "verbose"}.validate()
+	if err == nil || !strings.Contains(err.Error(), "Invalid setting driverLogLevel") {
+		t.Error("Expected error about unknown log level, but was", err)
+	}
+}
+
+func TestDriverLoggerIsConfigured(t *testing.T) {
+	configure, err := neo4JSettings{DriverLogLevel: "debug"}.driverConfig("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := config.Config{}
+	configure(&c)
+
+	logger, ok := c.Log.(*driverLogger)
+	if !ok || logger.datasourceId != "test" || logger.level != neo4jlog.DEBUG {
+		t.Error("Expected driver logger of the datasource, but was", c.Log)
+	}
+
+	configure, err = neo4JSettings{DriverLogLevel: "off"}.driverConfig("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = config.Config{}
+	configure(&c)
+	if c.Log != nil {
+		t.Error("Expected no driver logger, but was", c.Log)
+	}
+}
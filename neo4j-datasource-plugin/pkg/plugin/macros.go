@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+)
+
+// a macro starts with $__ followed by its name and an opening parenthesis, e.g. $__timeFilter(n.ts)
+var macroPattern = regexp.MustCompile(`^\$__(\w+)\(`)
+
+// expands a macro with its arguments into cypher
+type macroFunc func(query neo4JQuery, args []string) (string, error)
+
+var macros = map[string]macroFunc{
+	"timeFilter":        timeFilterMacro,
+	"timeFrom":          timeFromMacro,
+	"timeTo":            timeToMacro,
+	"unixEpochFilter":   unixEpochFilterMacro,
+	"unixEpochFrom":     unixEpochFromMacro,
+	"unixEpochTo":       unixEpochToMacro,
+	"unixEpochMsFilter": unixEpochMsFilterMacro,
+	"timeGroup":         timeGroupMacro,
+}
+
+// expands all macros of the cypher query into expressions of the time range parameters.
+// Strings, quoted names and comments are kept as they are.
+func expandMacros(query neo4JQuery) (string, error) {
+	cypher := query.CypherQuery
+	var expanded strings.Builder
+
+	for i := 0; i < len(cypher); {
+		if end := skipLiteral(cypher, i); end > i {
+			expanded.WriteString(cypher[i:end])
+			i = end
+			continue
+		}
+
+		var loc []int
+		if strings.HasPrefix(cypher[i:], "$__") {
+			loc = macroPattern.FindStringSubmatchIndex(cypher[i:])
+		}
+		if loc == nil {
+			expanded.WriteByte(cypher[i])
+			i++
+			continue
+		}
+
+		name := cypher[i+loc[2] : i+loc[3]]
+		macro, exists := macros[name]
+		if !exists {
+			return "", fmt.Errorf("Unknown macro '$__%s'", name)
+		}
+
+		args, end, err := parseMacroArgs(cypher[i+loc[1]:])
+		if err != nil {
+			return "", fmt.Errorf("Macro '$__%s' is invalid: %w", name, err)
+		}
+
+		value, err := macro(query, args)
+		if err != nil {
+			return "", fmt.Errorf("Macro '$__%s' is invalid: %w", name, err)
+		}
+
+		expanded.WriteString(value)
+		i += loc[1] + end
+	}
+
+	return expanded.String(), nil
+}
+
+// returns the end of the string, quoted name or comment, which starts at i,
+// or i if none starts there. Unclosed literals end at the end of the query.
+func skipLiteral(s string, i int) int {
+	switch {
+	case s[i] == '\'' || s[i] == '"':
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				j++
+			case s[i]:
+				return j + 1
+			}
+		}
+		return len(s)
+	case s[i] == '`':
+		if j := strings.IndexByte(s[i+1:], '`'); j >= 0 {
+			return i + j + 2
+		}
+		return len(s)
+	case strings.HasPrefix(s[i:], "//"):
+		if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+			return i + j
+		}
+		return len(s)
+	case strings.HasPrefix(s[i:], "/*"):
+		if j := strings.Index(s[i+2:], "*/"); j >= 0 {
+			return i + j + 4
+		}
+		return len(s)
+	}
+	return i
+}
+
+// splits the arguments of a macro at top level commas until the closing parenthesis.
+// Returns the arguments and the position after the closing parenthesis.
+func parseMacroArgs(s string) ([]string, int, error) {
+	var args []string
+	depth := 0
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		if end := skipLiteral(s, i); end > i {
+			i = end - 1
+			continue
+		}
+
+		switch c := s[i]; {
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' && depth == 0:
+			arg := strings.TrimSpace(s[start:i])
+			if arg != "" || len(args) > 0 {
+				args = append(args, arg)
+			}
+			return args, i + 1, nil
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	return nil, 0, fmt.Errorf("missing closing parenthesis")
+}
+
+func expectArgs(args []string, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("expected %d argument(s), but got %d", count, len(args))
+	}
+	for _, arg := range args {
+		if arg == "" {
+			return fmt.Errorf("arguments must not be empty")
+		}
+	}
+	return nil
+}
+
+// the macros reference the time range parameters instead of literal values,
+// so that the query text does not change with the time range and its plan can be cached.
+var (
+	fromParam = "$" + PARAM_FROM
+	toParam   = "$" + PARAM_TO
+)
+
+func timeFilterMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s >= %s AND %s <= %s)", args[0], fromParam, args[0], toParam), nil
+}
+
+func timeFromMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return fromParam, nil
+}
+
+func timeToMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return toParam, nil
+}
+
+func unixEpochFilterMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s >= %s.epochSeconds AND %s <= %s.epochSeconds)", args[0], fromParam, args[0], toParam), nil
+}
+
+func unixEpochFromMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return fromParam + ".epochSeconds", nil
+}
+
+func unixEpochToMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return toParam + ".epochSeconds", nil
+}
+
+func unixEpochMsFilterMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s >= %s.epochMillis AND %s <= %s.epochMillis)", args[0], fromParam, args[0], toParam), nil
+}
+
+// groups a temporal value into buckets of the given interval.
+// The interval is either $__interval or a duration like 5m.
+func timeGroupMacro(query neo4JQuery, args []string) (string, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return "", err
+	}
+
+	// $__interval references the parameter, a fixed interval is constant anyway
+	if args[1] == "$"+PARAM_INTERVAL {
+		if query.Interval.Milliseconds() <= 0 {
+			return "", fmt.Errorf("interval must be at least 1ms")
+		}
+		intervalMs := "$" + PARAM_INTERVAL_MS
+		return fmt.Sprintf("datetime({epochMillis: (%s).epochMillis / %s * %s})", args[0], intervalMs, intervalMs), nil
+	}
+
+	interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+	if err != nil {
+		return "", err
+	}
+
+	intervalMs := interval.Milliseconds()
+	if intervalMs <= 0 {
+		return "", fmt.Errorf("interval must be at least 1ms")
+	}
+
+	return fmt.Sprintf("datetime({epochMillis: (%s).epochMillis / %d * %d})", args[0], intervalMs, intervalMs), nil
+}
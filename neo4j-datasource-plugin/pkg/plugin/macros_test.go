@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func macroTestQuery(cypher string) neo4JQuery {
+	return neo4JQuery{
+		CypherQuery: cypher,
+		TimeRange: backend.TimeRange{
+			From: time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+			To:   time.Date(2023, 8, 1, 11, 0, 0, 0, time.UTC),
+		},
+		Interval: time.Minute,
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		cypher   string
+		expected string
+	}{
+		{
+			"MATCH (n) RETURN n",
+			"MATCH (n) RETURN n",
+		},
+		{
+			"MATCH (n) WHERE $__timeFilter(n.timestamp) RETURN n",
+			"MATCH (n) WHERE (n.timestamp >= $__from AND n.timestamp <= $__to) RETURN n",
+		},
+		{
+			"RETURN $__timeFrom() AS from, $__timeTo() AS to",
+			"RETURN $__from AS from, $__to AS to",
+		},
+		{
+			"MATCH (n) WHERE $__unixEpochFilter(n.ts) RETURN n",
+			"MATCH (n) WHERE (n.ts >= $__from.epochSeconds AND n.ts <= $__to.epochSeconds) RETURN n",
+		},
+		{
+			"RETURN $__unixEpochFrom(), $__unixEpochTo()",
+			"RETURN $__from.epochSeconds, $__to.epochSeconds",
+		},
+		{
+			"MATCH (n) WHERE $__unixEpochMsFilter(n.ts) RETURN n",
+			"MATCH (n) WHERE (n.ts >= $__from.epochMillis AND n.ts <= $__to.epochMillis) RETURN n",
+		},
+		{
+			"MATCH (n) RETURN $__timeGroup(n.ts, $__interval) AS time, count(*)",
+			"MATCH (n) RETURN datetime({epochMillis: (n.ts).epochMillis / $__interval_ms * $__interval_ms}) AS time, count(*)",
+		},
+		{
+			"MATCH (n) RETURN $__timeGroup(n.ts, 5m) AS time, count(*)",
+			"MATCH (n) RETURN datetime({epochMillis: (n.ts).epochMillis / 300000 * 300000}) AS time, count(*)",
+		},
+		{
+			"MATCH (n) RETURN $__timeGroup(datetime(n.created), '1h') AS time",
+			"MATCH (n) RETURN datetime({epochMillis: (datetime(n.created)).epochMillis / 3600000 * 3600000}) AS time",
+		},
+		{
+			"MATCH (n) RETURN $__timeGroup(n.ts + duration('PT1H'), $__interval) AS time",
+			"MATCH (n) RETURN datetime({epochMillis: (n.ts + duration('PT1H')).epochMillis / $__interval_ms * $__interval_ms}) AS time",
+		},
+		{
+			"MATCH (n) WHERE $__timeFilter(coalesce(n.a, n.b)) AND n.name = 'a, b)' RETURN $__interval_ms",
+			"MATCH (n) WHERE (coalesce(n.a, n.b) >= $__from AND coalesce(n.a, n.b) <= $__to) AND n.name = 'a, b)' RETURN $__interval_ms",
+		},
+		{
+			"RETURN '$__timeFrom()' AS s, \"it\\\"s $__timeTo(\" AS t, `$__timeFrom()` AS u, $__timeFrom() AS v",
+			"RETURN '$__timeFrom()' AS s, \"it\\\"s $__timeTo(\" AS t, `$__timeFrom()` AS u, $__from AS v",
+		},
+		{
+			"MATCH (n) // $__timeFilter(\nWHERE $__timeFilter(n.ts) /* $__doesNotExist() */ RETURN n",
+			"MATCH (n) // $__timeFilter(\nWHERE (n.ts >= $__from AND n.ts <= $__to) /* $__doesNotExist() */ RETURN n",
+		},
+		{
+			"MATCH (n) WHERE $__timeFilter(coalesce(n.ts, 'it\\'s )')) RETURN n",
+			"MATCH (n) WHERE (coalesce(n.ts, 'it\\'s )') >= $__from AND coalesce(n.ts, 'it\\'s )') <= $__to) RETURN n",
+		},
+	}
+
+	for _, test := range tests {
+		expanded, err := expandMacros(macroTestQuery(test.cypher))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if expanded != test.expected {
+			t.Errorf("Expected\n%s\nbut was\n%s", test.expected, expanded)
+		}
+	}
+}
+
+func TestExpandInvalidMacros(t *testing.T) {
+	tests := []struct {
+		cypher          string
+		expectedMessage string
+	}{
+		{"RETURN $__doesNotExist(n)", "Unknown macro '$__doesNotExist'"},
+		{"WHERE $__timeFilter(n.ts RETURN n", "missing closing parenthesis"},
+		{"WHERE $__timeFilter() RETURN n", "expected 1 argument(s), but got 0"},
+		{"RETURN $__timeFrom(n.ts)", "expected 0 argument(s), but got 1"},
+		{"RETURN $__timeGroup(n.ts)", "expected 2 argument(s), but got 1"},
+		{"RETURN $__timeGroup(n.ts, often)", "Macro '$__timeGroup' is invalid"},
+		{"RETURN $__timeGroup(n.ts, 0s)", "interval must be at least 1ms"},
+	}
+
+	for _, test := range tests {
+		_, err := expandMacros(macroTestQuery(test.cypher))
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error containing %s, but was %v", test.expectedMessage, err)
+		}
+	}
+}
+
+func TestMacrosInCypher(t *testing.T) {
+	skipIfIsShort(t)
+
+	query := macroTestQuery("With datetime('2023-08-01T10:30:10Z') as ts where $__timeFilter(ts) return $__timeGroup(ts, $__interval) as time")
+	query.Format = "table"
+
+	expectedFrame := data.NewFrame("response",
+		data.NewField("time", nil, []*time.Time{
+			ptrT(time.Date(2023, 8, 1, 10, 30, 0, 0, time.UTC)),
+		}),
+	)
+	expectedFrame.SetMeta(&data.FrameMeta{
+		ExecutedQueryString: "With datetime('2023-08-01T10:30:10Z') as ts where (ts >= $__from AND ts <= $__to) return datetime({epochMillis: (ts).epochMillis / $__interval_ms * $__interval_ms}) as time",
+	})
+
+	runNeo4JIntegrationTableQueryTest(t, query, expectedFrame)
+}
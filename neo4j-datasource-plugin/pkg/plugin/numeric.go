@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// types of frames the numeric format can return
+// https://grafana.com/developers/dataplane/numeric
+const (
+	NUMERIC_TYPE_LONG  string = "long"
+	NUMERIC_TYPE_MULTI string = "multi"
+)
+
+// Return numeric response for alerting without time column.
+// String and boolean columns are used as dimensions and numeric columns as values,
+// so that each row results in a separate alert instance.
+func toNumericResponse(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit, numericType string) (backend.DataResponse, error) {
+	response, err := toDataResponse(ctx, result, limit)
+	if err != nil {
+		return response, err
+	}
+
+	frames, err := toNumericFrames(response.Frames[0], numericType)
+	if err != nil {
+		return response, newValidationError(err)
+	}
+
+	response.Frames = frames
+	return response, nil
+}
+
+func toNumericFrames(frame *data.Frame, numericType string) (data.Frames, error) {
+	var dimensions []*data.Field
+	var values []*data.Field
+
+	for _, field := range frame.Fields {
+		switch {
+		case field.Type().Numeric():
+			values = append(values, field)
+		case field.Type() == data.FieldTypeNullableString || field.Type() == data.FieldTypeNullableBool:
+			dimension := data.NewField(field.Name, nil, make([]string, field.Len()))
+			for row := 0; row < field.Len(); row++ {
+				dimension.Set(row, toLabelValue(field, row))
+			}
+			dimensions = append(dimensions, dimension)
+		default:
+			return nil, errors.New("Numeric format supports only string, boolean and numeric columns, but column '" + field.Name + "' is not. Use time series format for temporal values")
+		}
+	}
+
+	if len(values) == 0 && frame.Rows() > 0 {
+		return nil, errors.New("Numeric format requires at least one column with numeric values")
+	}
+
+	switch numericType {
+	case NUMERIC_TYPE_LONG, "":
+		return data.Frames{toNumericLongFrame(frame.Name, dimensions, values)}, nil
+	case NUMERIC_TYPE_MULTI:
+		return toNumericMultiFrames(dimensions, values), nil
+	default:
+		return nil, errors.New("Unknown numeric type '" + numericType + "'")
+	}
+}
+
+func toNumericLongFrame(name string, dimensions []*data.Field, values []*data.Field) *data.Frame {
+	frame := data.NewFrame(name, append(dimensions, values...)...)
+	frame.Meta = &data.FrameMeta{
+		Type:        data.FrameTypeNumericLong,
+		TypeVersion: data.FrameTypeVersion{0, 1},
+	}
+	return frame
+}
+
+// creates one frame per row and value column with the dimensions as labels
+func toNumericMultiFrames(dimensions []*data.Field, values []*data.Field) data.Frames {
+	var frames data.Frames
+	for _, valueField := range values {
+		for row := 0; row < valueField.Len(); row++ {
+			labels := data.Labels{}
+			for _, dimension := range dimensions {
+				labels[dimension.Name] = dimension.At(row).(string)
+			}
+
+			value := data.NewFieldFromFieldType(valueField.Type(), 1)
+			value.Name = valueField.Name
+			value.Labels = labels
+			value.Set(0, valueField.At(row))
+
+			frame := data.NewFrame(valueField.Name+"_"+strconv.Itoa(row), value)
+			frame.Meta = &data.FrameMeta{
+				Type:        data.FrameTypeNumericMulti,
+				TypeVersion: data.FrameTypeVersion{0, 1},
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// result as returned by: RETURN service, healthy, count(*)
+func numericTestFrame() *data.Frame {
+	return data.NewFrame("response",
+		data.NewField("service", nil, []*string{ptrS("api"), ptrS("db"), nil}),
+		data.NewField("healthy", nil, []*bool{ptrB(true), ptrB(false), ptrB(true)}),
+		data.NewField("count(*)", nil, []*int64{ptrI(3), ptrI(1), ptrI(7)}),
+	)
+}
+
+func TestNumericLong(t *testing.T) {
+	frames, err := toNumericFrames(numericTestFrame(), "long")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := data.NewFrame("response",
+		data.NewField("service", nil, []string{"api", "db", ""}),
+		data.NewField("healthy", nil, []string{"true", "false", "true"}),
+		data.NewField("count(*)", nil, []*int64{ptrI(3), ptrI(1), ptrI(7)}),
+	).SetMeta(&data.FrameMeta{Type: data.FrameTypeNumericLong, TypeVersion: data.FrameTypeVersion{0, 1}})
+
+	if len(frames) != 1 {
+		t.Fatal("Frames len is not 1")
+	}
+
+	diff := cmp.Diff(frames[0], expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestNumericMulti(t *testing.T) {
+	frames, err := toNumericFrames(numericTestFrame(), "multi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &data.FrameMeta{Type: data.FrameTypeNumericMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+	expected := data.Frames{
+		data.NewFrame("count(*)_0",
+			data.NewField("count(*)", data.Labels{"service": "api", "healthy": "true"}, []*int64{ptrI(3)}),
+		).SetMeta(meta),
+		data.NewFrame("count(*)_1",
+			data.NewField("count(*)", data.Labels{"service": "db", "healthy": "false"}, []*int64{ptrI(1)}),
+		).SetMeta(meta),
+		data.NewFrame("count(*)_2",
+			data.NewField("count(*)", data.Labels{"service": "", "healthy": "true"}, []*int64{ptrI(7)}),
+		).SetMeta(meta),
+	}
+
+	diff := cmp.Diff(frames, expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestNumericWithUnsupportedColumns(t *testing.T) {
+	withTime := data.NewFrame("response",
+		data.NewField("t", nil, []*time.Time{ptrT(time.Now())}),
+		data.NewField("value", nil, []*int64{ptrI(1)}),
+	)
+	withoutValue := data.NewFrame("response",
+		data.NewField("service", nil, []*string{ptrS("api")}),
+	)
+
+	_, err := toNumericFrames(withTime, "long")
+	if err == nil || !strings.Contains(err.Error(), "column 't'") {
+		t.Error("Expected error about time column, but was", err)
+	}
+
+	_, err = toNumericFrames(withoutValue, "long")
+	if err == nil || !strings.Contains(err.Error(), "numeric values") {
+		t.Error("Expected error about missing value column, but was", err)
+	}
+}
@@ -0,0 +1,161 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// names of the parameters that are bound to every cypher query.
+// They are referenced inside cypher via $__from, $__to etc.
+const (
+	PARAM_FROM            string = "__from"
+	PARAM_TO              string = "__to"
+	PARAM_INTERVAL        string = "__interval"
+	PARAM_INTERVAL_MS     string = "__interval_ms"
+	PARAM_MAX_DATA_POINTS string = "__maxDataPoints"
+
+	// user defined parameters must not start with this prefix
+	RESERVED_PARAM_PREFIX string = "__"
+)
+
+// types of user defined parameters
+const (
+	PARAM_TYPE_STRING   string = "string"
+	PARAM_TYPE_INT      string = "int"
+	PARAM_TYPE_FLOAT    string = "float"
+	PARAM_TYPE_BOOL     string = "bool"
+	PARAM_TYPE_LIST     string = "list"
+	PARAM_TYPE_DATETIME string = "datetime"
+	PARAM_TYPE_DURATION string = "duration"
+)
+
+// user defined parameter of a query.
+// The value is always transferred as string, because it is interpolated
+// with dashboard variables by the frontend. It is converted according to its type.
+type neo4JParameter struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// builds the native cypher parameters for the given query.
+// Times are passed as time.Time which the driver sends as neo4j DateTime,
+// the interval as neo4j Duration and numbers as neo4j Integer.
+func toParameters(query neo4JQuery) (map[string]interface{}, error) {
+	parameters := map[string]interface{}{
+		PARAM_FROM:            query.TimeRange.From,
+		PARAM_TO:              query.TimeRange.To,
+		PARAM_INTERVAL:        toDuration(query.Interval),
+		PARAM_INTERVAL_MS:     query.Interval.Milliseconds(),
+		PARAM_MAX_DATA_POINTS: query.MaxDataPoints,
+	}
+
+	for name, parameter := range query.Parameters {
+		if name == "" {
+			return nil, errors.New("Parameter name must not be empty")
+		}
+
+		if strings.HasPrefix(name, RESERVED_PARAM_PREFIX) {
+			return nil, fmt.Errorf("Parameter '%s' is invalid: names starting with '%s' are reserved", name, RESERVED_PARAM_PREFIX)
+		}
+
+		value, err := toParameterValue(parameter)
+		if err != nil {
+			return nil, fmt.Errorf("Parameter '%s' is invalid: %w", name, err)
+		}
+		parameters[name] = value
+	}
+
+	return parameters, nil
+}
+
+// converts the string value of a parameter into the go type the driver maps to the neo4j type.
+// https://github.com/neo4j/neo4j-go-driver#value-types
+func toParameterValue(parameter neo4JParameter) (interface{}, error) {
+	switch parameter.Type {
+	case PARAM_TYPE_STRING, "":
+		return parameter.Value, nil
+	case PARAM_TYPE_INT:
+		return strconv.ParseInt(strings.TrimSpace(parameter.Value), 10, 64)
+	case PARAM_TYPE_FLOAT:
+		return strconv.ParseFloat(strings.TrimSpace(parameter.Value), 64)
+	case PARAM_TYPE_BOOL:
+		return strconv.ParseBool(strings.TrimSpace(parameter.Value))
+	case PARAM_TYPE_LIST:
+		return toListValue(parameter.Value)
+	case PARAM_TYPE_DATETIME:
+		return toDateTimeValue(parameter.Value)
+	case PARAM_TYPE_DURATION:
+		d, err := gtime.ParseDuration(strings.TrimSpace(parameter.Value))
+		if err != nil {
+			return nil, err
+		}
+		return toDuration(d), nil
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", parameter.Type)
+	}
+}
+
+// list values are expected as json array, e.g. ["a","b"] or [1,2].
+func toListValue(value string) ([]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+
+	var list []interface{}
+	err := decoder.Decode(&list)
+	if err != nil {
+		return nil, errors.New("list value must be a json array")
+	}
+
+	for i, item := range list {
+		list[i] = fromJsonValue(item)
+	}
+	return list, nil
+}
+
+// converts json numbers to int64 or float64, which are the types the driver understands.
+func fromJsonValue(val interface{}) interface{} {
+	switch t := val.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case []interface{}:
+		for i, item := range t {
+			t[i] = fromJsonValue(item)
+		}
+		return t
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = fromJsonValue(item)
+		}
+		return t
+	default:
+		return val
+	}
+}
+
+// datetime values are expected as RFC3339 or as unix epoch in milliseconds like ${__from}.
+func toDateTimeValue(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if epochMs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(epochMs).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func toDuration(d time.Duration) dbtype.Duration {
+	return dbtype.Duration{
+		Seconds: int64(d / time.Second),
+		Nanos:   int(d % time.Second),
+	}
+}
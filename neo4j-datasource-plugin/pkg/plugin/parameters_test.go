@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestTimeRangeParameters(t *testing.T) {
+	from := time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 8, 1, 11, 0, 0, 0, time.UTC)
+
+	query := neo4JQuery{
+		TimeRange:     backend.TimeRange{From: from, To: to},
+		Interval:      90 * time.Second,
+		MaxDataPoints: 1000,
+	}
+
+	expected := map[string]interface{}{
+		"__from":          from,
+		"__to":            to,
+		"__interval":      dbtype.Duration{Seconds: 90},
+		"__interval_ms":   int64(90000),
+		"__maxDataPoints": int64(1000),
+	}
+
+	parameters, err := toParameters(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(parameters, expected)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestUserDefinedParameters(t *testing.T) {
+	query := neo4JQuery{
+		Parameters: map[string]neo4JParameter{
+			"name":     {Type: "string", Value: "Keanu Reeves"},
+			"untyped":  {Value: "text"},
+			"born":     {Type: "int", Value: "1964"},
+			"rating":   {Type: "float", Value: "8.7"},
+			"active":   {Type: "bool", Value: "true"},
+			"names":    {Type: "list", Value: `["Neo","Trinity"]`},
+			"years":    {Type: "list", Value: `[1999, 2003.5]`},
+			"since":    {Type: "datetime", Value: "2023-08-01T10:00:00Z"},
+			"sinceMs":  {Type: "datetime", Value: "1690884000000"},
+			"lookback": {Type: "duration", Value: "1h30m"},
+		},
+	}
+
+	parameters, err := toParameters(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":     "Keanu Reeves",
+		"untyped":  "text",
+		"born":     int64(1964),
+		"rating":   8.7,
+		"active":   true,
+		"names":    []interface{}{"Neo", "Trinity"},
+		"years":    []interface{}{int64(1999), 2003.5},
+		"since":    time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+		"sinceMs":  time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+		"lookback": dbtype.Duration{Seconds: 5400},
+	}
+
+	for name, value := range expected {
+		diff := cmp.Diff(parameters[name], value)
+		if diff != "" {
+			t.Error(name + ": " + diff)
+		}
+	}
+}
+
+func TestInvalidUserDefinedParameters(t *testing.T) {
+	invalid := map[string]neo4JParameter{
+		"__from":  {Type: "string", Value: "overwrite"},
+		"born":    {Type: "int", Value: "nineteen"},
+		"names":   {Type: "list", Value: "Neo"},
+		"since":   {Type: "datetime", Value: "yesterday"},
+		"unknown": {Type: "point", Value: "1,2"},
+	}
+
+	for name, parameter := range invalid {
+		query := neo4JQuery{
+			Parameters: map[string]neo4JParameter{name: parameter},
+		}
+
+		_, err := toParameters(query)
+		if err == nil || !strings.Contains(err.Error(), "'"+name+"'") {
+			t.Errorf("Expected error for parameter %s, but was %v", name, err)
+		}
+	}
+}
+
+func TestUserDefinedParametersInCypher(t *testing.T) {
+	skipIfIsShort(t)
+	expectedFrame := data.NewFrame("response",
+		data.NewField("m.title", nil, []*string{
+			ptrS("The Matrix"),
+		}),
+	)
+
+	query := neo4JQuery{
+		CypherQuery: "Match(m:Movie) where m.title in $titles and m.released = $released return m.title",
+		Format:      "table",
+		Parameters: map[string]neo4JParameter{
+			"titles":   {Type: "list", Value: `["The Matrix"]`},
+			"released": {Type: "int", Value: "1999"},
+		},
+	}
+
+	runNeo4JIntegrationTableQueryTest(t, query, expectedFrame)
+}
+
+func TestTimeRangeParametersInCypher(t *testing.T) {
+	skipIfIsShort(t)
+	expectedFrame := data.NewFrame("response",
+		data.NewField("from", nil, []*time.Time{
+			ptrT(time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC)),
+		}),
+		data.NewField("interval", nil, []*int64{
+			ptrI(60000),
+		}),
+	)
+
+	query := neo4JQuery{
+		CypherQuery: "return $__from as from, $__interval_ms as interval",
+		Format:      "table",
+		TimeRange: backend.TimeRange{
+			From: time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC),
+			To:   time.Date(2023, 8, 1, 11, 0, 0, 0, time.UTC),
+		},
+		Interval: time.Minute,
+	}
+
+	runNeo4JIntegrationTableQueryTest(t, query, expectedFrame)
+}
@@ -0,0 +1,164 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// query types, which return the execution plan of the query instead of the result
+const (
+	QUERY_TYPE_EXPLAIN string = "explain"
+	QUERY_TYPE_PROFILE string = "profile"
+)
+
+func isPlanQuery(queryType string) bool {
+	return queryType == QUERY_TYPE_EXPLAIN || queryType == QUERY_TYPE_PROFILE
+}
+
+// prefixes the cypher, so that the plan of the query is returned in the result summary
+func toPlanCypher(cypher string, queryType string) string {
+	switch queryType {
+	case QUERY_TYPE_EXPLAIN:
+		return "EXPLAIN " + cypher
+	case QUERY_TYPE_PROFILE:
+		return "PROFILE " + cypher
+	default:
+		return cypher
+	}
+}
+
+// operator of the plan, which is the same for explained and profiled plans.
+// The statistics of the execution are only set for profiled plans.
+type planOperator struct {
+	operator      string
+	details       string
+	identifiers   []string
+	estimatedRows float64
+	rows          *int64
+	dbHits        *int64
+	time          *int64
+	children      []*planOperator
+}
+
+func fromPlan(plan neo4j.Plan) *planOperator {
+	op := newPlanOperator(plan.Operator(), plan.Arguments(), plan.Identifiers())
+	for _, child := range plan.Children() {
+		op.children = append(op.children, fromPlan(child))
+	}
+	return op
+}
+
+func fromProfiledPlan(plan neo4j.ProfiledPlan) *planOperator {
+	op := newPlanOperator(plan.Operator(), plan.Arguments(), plan.Identifiers())
+	op.rows = ptr(plan.Records())
+	op.dbHits = ptr(plan.DbHits())
+	op.time = ptr(plan.Time())
+	for _, child := range plan.Children() {
+		op.children = append(op.children, fromProfiledPlan(child))
+	}
+	return op
+}
+
+func newPlanOperator(operator string, arguments map[string]any, identifiers []string) *planOperator {
+	op := &planOperator{operator: operator, identifiers: identifiers}
+	if details, ok := arguments["Details"]; ok {
+		op.details = fmt.Sprint(details)
+	}
+	switch estimatedRows := arguments["EstimatedRows"].(type) {
+	case float64:
+		op.estimatedRows = estimatedRows
+	case int64:
+		op.estimatedRows = float64(estimatedRows)
+	}
+	return op
+}
+
+// returns the plan of the result summary as table with one operator per row and as flame graph
+func toPlanResponse(summary neo4j.ResultSummary, queryType string) (backend.DataResponse, error) {
+	response := backend.DataResponse{}
+
+	var root *planOperator
+	switch {
+	case queryType == QUERY_TYPE_PROFILE && summary.Profile() != nil:
+		root = fromProfiledPlan(summary.Profile())
+	case summary.Plan() != nil:
+		root = fromPlan(summary.Plan())
+	default:
+		return response, fmt.Errorf("Neo4j returned no plan for the query")
+	}
+
+	response.Frames = append(response.Frames, toPlanFrame(root), toFlameGraphFrame(root, queryType))
+	return response, nil
+}
+
+// creates a frame with one operator per row, the parent id of the root operator is null
+func toPlanFrame(root *planOperator) *data.Frame {
+	frame := data.NewFrame("plan",
+		data.NewField("id", nil, []int64{}),
+		data.NewField("parentId", nil, []*int64{}),
+		data.NewField("operator", nil, []string{}),
+		data.NewField("details", nil, []string{}),
+		data.NewField("identifiers", nil, []string{}),
+		data.NewField("estimatedRows", nil, []float64{}),
+		data.NewField("rows", nil, []*int64{}),
+		data.NewField("dbHits", nil, []*int64{}),
+		data.NewField("time", nil, []*int64{}),
+	)
+	frame.Fields[8].Config = &data.FieldConfig{Unit: "ns"}
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+
+	var id int64
+	var walk func(op *planOperator, parentId *int64)
+	walk = func(op *planOperator, parentId *int64) {
+		opId := id
+		id++
+		frame.AppendRow(opId, parentId, op.operator, op.details, strings.Join(op.identifiers, ", "), op.estimatedRows, op.rows, op.dbHits, op.time)
+		for _, child := range op.children {
+			walk(child, ptr(opId))
+		}
+	}
+	walk(root, nil)
+
+	return frame
+}
+
+// creates a frame for the flame graph panel with the operators in depth first order.
+// The value of profiled plans are the db hits, otherwise the estimated rows.
+func toFlameGraphFrame(root *planOperator, queryType string) *data.Frame {
+	frame := data.NewFrame("flamegraph",
+		data.NewField("level", nil, []int64{}),
+		data.NewField("value", nil, []int64{}),
+		data.NewField("self", nil, []int64{}),
+		data.NewField("label", nil, []string{}),
+	)
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeFlameGraph}
+
+	selfValue := func(op *planOperator) int64 {
+		if queryType == QUERY_TYPE_PROFILE && op.dbHits != nil {
+			return *op.dbHits
+		}
+		return int64(math.Round(op.estimatedRows))
+	}
+
+	var walk func(op *planOperator, level int64) int64
+	walk = func(op *planOperator, level int64) int64 {
+		row := frame.Rows()
+		self := selfValue(op)
+		frame.AppendRow(level, self, self, op.operator)
+
+		total := self
+		for _, child := range op.children {
+			total += walk(child, level+1)
+		}
+		frame.Fields[1].Set(row, total)
+		return total
+	}
+	walk(root, 0)
+
+	return frame
+}
@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// plan of: PROFILE MATCH (n:Person) RETURN n
+func testPlan() *planOperator {
+	return &planOperator{
+		operator:      "ProduceResults@neo4j",
+		details:       "n",
+		identifiers:   []string{"n"},
+		estimatedRows: 10,
+		rows:          ptr(int64(10)),
+		dbHits:        ptr(int64(0)),
+		time:          ptr(int64(100)),
+		children: []*planOperator{
+			{
+				operator:      "NodeByLabelScan@neo4j",
+				details:       "n:Person",
+				identifiers:   []string{"n"},
+				estimatedRows: 10,
+				rows:          ptr(int64(10)),
+				dbHits:        ptr(int64(11)),
+				time:          ptr(int64(200)),
+			},
+		},
+	}
+}
+
+func TestToPlanCypher(t *testing.T) {
+	if cypher := toPlanCypher("RETURN 1", "explain"); cypher != "EXPLAIN RETURN 1" {
+		t.Error("Expected EXPLAIN prefix, but was", cypher)
+	}
+	if cypher := toPlanCypher("RETURN 1", "profile"); cypher != "PROFILE RETURN 1" {
+		t.Error("Expected PROFILE prefix, but was", cypher)
+	}
+	if cypher := toPlanCypher("RETURN 1", ""); cypher != "RETURN 1" {
+		t.Error("Expected unchanged cypher, but was", cypher)
+	}
+}
+
+func TestPlanFrame(t *testing.T) {
+	frame := toPlanFrame(testPlan())
+
+	expected := data.NewFrame("plan",
+		data.NewField("id", nil, []int64{0, 1}),
+		data.NewField("parentId", nil, []*int64{nil, ptrI(0)}),
+		data.NewField("operator", nil, []string{"ProduceResults@neo4j", "NodeByLabelScan@neo4j"}),
+		data.NewField("details", nil, []string{"n", "n:Person"}),
+		data.NewField("identifiers", nil, []string{"n", "n"}),
+		data.NewField("estimatedRows", nil, []float64{10, 10}),
+		data.NewField("rows", nil, []*int64{ptrI(10), ptrI(10)}),
+		data.NewField("dbHits", nil, []*int64{ptrI(0), ptrI(11)}),
+		data.NewField("time", nil, []*int64{ptrI(100), ptrI(200)}).SetConfig(&data.FieldConfig{Unit: "ns"}),
+	).SetMeta(&data.FrameMeta{PreferredVisualization: data.VisTypeTable})
+
+	diff := cmp.Diff(frame, expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestFlameGraphFrame(t *testing.T) {
+	expectedProfile := data.NewFrame("flamegraph",
+		data.NewField("level", nil, []int64{0, 1}),
+		data.NewField("value", nil, []int64{11, 11}),
+		data.NewField("self", nil, []int64{0, 11}),
+		data.NewField("label", nil, []string{"ProduceResults@neo4j", "NodeByLabelScan@neo4j"}),
+	).SetMeta(&data.FrameMeta{PreferredVisualization: data.VisTypeFlameGraph})
+
+	diff := cmp.Diff(toFlameGraphFrame(testPlan(), "profile"), expectedProfile, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+
+	// without profile the estimated rows are used
+	expectedExplain := data.NewFrame("flamegraph",
+		data.NewField("level", nil, []int64{0, 1}),
+		data.NewField("value", nil, []int64{20, 10}),
+		data.NewField("self", nil, []int64{10, 10}),
+		data.NewField("label", nil, []string{"ProduceResults@neo4j", "NodeByLabelScan@neo4j"}),
+	).SetMeta(&data.FrameMeta{PreferredVisualization: data.VisTypeFlameGraph})
+
+	diff = cmp.Diff(toFlameGraphFrame(testPlan(), "explain"), expectedExplain, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestProfileIntegration(t *testing.T) {
+	skipIfIsShort(t)
+
+	res := runNeo4JIntegrationQuery(t, neo4JQuery{
+		CypherQuery: "UNWIND range(1, 3) AS i RETURN i",
+		QueryType:   "profile",
+	})
+
+	if len(res.Frames) != 2 || res.Frames[0].Name != "plan" || res.Frames[1].Name != "flamegraph" {
+		t.Fatal("Expected plan and flamegraph frames, but was", res.Frames)
+	}
+
+	if res.Frames[0].Fields[6].At(0).(*int64) == nil {
+		t.Error("Expected rows of the profiled plan")
+	}
+}
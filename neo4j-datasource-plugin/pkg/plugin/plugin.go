@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"runtime/debug"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,12 +22,13 @@ import (
 // Datasource must implement required interfaces. This is important to do
 // since otherwise we will only get a not implemented error response from plugin in
 // runtime. Datasource instance implements backend.QueryDataHandler,
-// backend.CheckHealthHandler.Implementing instancemgmt.InstanceDisposer
+// backend.CheckHealthHandler, backend.CallResourceHandler.Implementing instancemgmt.InstanceDisposer
 // is useful to clean up resources used by previous datasource instance when a new datasource
 // instance created upon datasource settings changed.
 var (
-	_ backend.QueryDataHandler   = (*Neo4JDatasource)(nil)
-	_ backend.CheckHealthHandler = (*Neo4JDatasource)(nil)
+	_ backend.QueryDataHandler    = (*Neo4JDatasource)(nil)
+	_ backend.CheckHealthHandler  = (*Neo4JDatasource)(nil)
+	_ backend.CallResourceHandler = (*Neo4JDatasource)(nil)
 	_ backend.DataSourceInstanceSettings
 	_ instancemgmt.InstanceDisposer = (*Neo4JDatasource)(nil)
 )
@@ -39,10 +43,16 @@ type Neo4JDatasource struct {
 	id       string
 	settings neo4JSettings
 	driver   neo4j.DriverWithContext
+
+	// limits the number of queries executed at the same time
+	querySlots chan struct{}
+
+	resourceHandler backend.CallResourceHandler
+	schemaCache     *schemaCache
 }
 
 // creates a new datasource instance.
-func NewNeo4JDatasource(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+func NewNeo4JDatasource(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 	id := uuid.New().String()
 	log.DefaultLogger.Debug("Create Datasource", DATASOURCE_UID, id)
 	neo4JSettings, err := unmarshalDataSourceSettings(settings)
@@ -52,21 +62,42 @@ func NewNeo4JDatasource(settings backend.DataSourceInstanceSettings) (instancemg
 		return nil, errors.New(errorMsg)
 	}
 
-	authToken := neo4j.NoAuth()
-	if neo4JSettings.Username != "" && neo4JSettings.Password != "" {
-		authToken = neo4j.BasicAuth(neo4JSettings.Username, neo4JSettings.Password, "")
+	err = neo4JSettings.validate()
+	if err != nil {
+		log.DefaultLogger.Error("Invalid DataSource settings", ERROR, err.Error())
+		return nil, err
 	}
 
-	driver, err := neo4j.NewDriverWithContext(neo4JSettings.Url, authToken)
+	authToken, err := neo4JSettings.authToken()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Neo4JDatasource{
-		id:       id,
-		settings: neo4JSettings,
-		driver:   driver,
-	}, nil
+	driverUrl, err := neo4JSettings.driverUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	driverConfig, err := neo4JSettings.driverConfig(id)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := neo4j.NewDriverWithContext(driverUrl, authToken, driverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource := &Neo4JDatasource{
+		id:          id,
+		settings:    neo4JSettings,
+		driver:      driver,
+		querySlots:  make(chan struct{}, neo4JSettings.maxConcurrentQueries()),
+		schemaCache: newSchemaCache(SCHEMA_CACHE_TTL),
+	}
+	datasource.resourceHandler = newResourceHandler(datasource)
+
+	return datasource, nil
 }
 
 // Dispose here tells plugin SDK that plugin wants to clean up resources when a new instance
@@ -87,129 +118,291 @@ func (d *Neo4JDatasource) QueryData(ctx context.Context, req *backend.QueryDataR
 
 	// create response struct
 	response := backend.NewQueryDataResponse()
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	identity := sessionIdentity{user: req.PluginContext.User}
+	if d.settings.OAuthPassThru {
+		identity.authorization = req.GetHTTPHeader(backend.OAuthIdentityTokenHeaderName)
+	}
 
-	// loop over queries and execute them individually.
+	// execute queries concurrently, each in its own session.
 	for _, q := range req.Queries {
+		wg.Add(1)
+		go func(q backend.DataQuery) {
+			defer wg.Done()
 
-		var res backend.DataResponse
+			res := d.handleQuery(ctx, q, identity)
 
-		// Unmarshal the JSON into our queryModel.
-		var neo4JQuery neo4JQuery
-		err := json.Unmarshal(q.JSON, &neo4JQuery)
-		if err != nil {
-			res.Error = err
+			mutex.Lock()
+			defer mutex.Unlock()
 			response.Responses[q.RefID] = res
-			continue
-		}
+		}(q)
+	}
 
-		neo4JQuery.RefID = q.RefID
-		neo4JQuery.QueryType = q.QueryType
-		neo4JQuery.Interval = q.Interval
-		neo4JQuery.MaxDataPoints = q.MaxDataPoints
-		neo4JQuery.TimeRange = q.TimeRange
+	wg.Wait()
+	return response, nil
+}
 
-		res, err = d.query(ctx, neo4JQuery)
-		if err != nil {
-			res.Error = err
+// executes a single query, as soon as the concurrency limit of the datasource allows it.
+// Errors and panics are returned as response of this query only.
+func (d *Neo4JDatasource) handleQuery(ctx context.Context, q backend.DataQuery, identity sessionIdentity) (res backend.DataResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.DefaultLogger.Error("Panic in query", DATASOURCE_UID, d.id, ERROR, r, "stack", string(debug.Stack()))
+			res = backend.DataResponse{
+				Error:       fmt.Errorf("InternalError! Query %s failed unexpectedly. Please review log for more details.", q.RefID),
+				Status:      backend.StatusInternal,
+				ErrorSource: backend.ErrorSourcePlugin,
+			}
 		}
+	}()
 
-		if res.Error != nil {
-			log.DefaultLogger.Error("Error in query", ERROR, res.Error)
-		}
+	select {
+	case d.querySlots <- struct{}{}:
+		defer func() { <-d.querySlots }()
+	case <-ctx.Done():
+		return d.errorResponse(backend.DataResponse{}, ctx.Err())
+	}
 
-		response.Responses[q.RefID] = res
+	// Unmarshal the JSON into our queryModel.
+	var neo4JQuery neo4JQuery
+	err := json.Unmarshal(q.JSON, &neo4JQuery)
+	if err != nil {
+		return d.errorResponse(backend.DataResponse{}, newValidationError(err))
 	}
 
-	return response, nil
+	neo4JQuery.RefID = q.RefID
+	neo4JQuery.QueryType = q.QueryType
+	neo4JQuery.Interval = q.Interval
+	neo4JQuery.MaxDataPoints = q.MaxDataPoints
+	neo4JQuery.TimeRange = q.TimeRange
+	neo4JQuery.identity = identity
+
+	res, err = d.query(ctx, neo4JQuery)
+	if err != nil {
+		res.Error = err
+	}
+
+	if res.Error != nil {
+		return d.errorResponse(res, res.Error)
+	}
+
+	return res
 }
 
-func (d *Neo4JDatasource) query(ctx context.Context, query neo4JQuery) (backend.DataResponse, error) {
-	log.DefaultLogger.Debug("Execute Cypher Query: '"+query.CypherQuery+"'", DATASOURCE_UID, d.id)
+// sets the classified error with its status and source on the response
+func (d *Neo4JDatasource) errorResponse(res backend.DataResponse, err error) backend.DataResponse {
+	qErr := classifyError(err)
+	res.Error = qErr
+	res.Status = qErr.status
+	res.ErrorSource = qErr.source
+	log.DefaultLogger.Error("Error in query", DATASOURCE_UID, d.id, ERROR, qErr.err, "status", int(qErr.status), "errorSource", qErr.source)
+	return res
+}
 
+func (d *Neo4JDatasource) query(ctx context.Context, query neo4JQuery) (backend.DataResponse, error) {
 	response := backend.DataResponse{}
 
-	session := d.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: d.settings.Database, AccessMode: neo4j.AccessModeRead})
-	defer session.Close(ctx)
+	cypher, err := expandMacros(query)
+	if err != nil {
+		return response, newValidationError(err)
+	}
 
-	result, err := session.Run(ctx, query.CypherQuery, map[string]interface{}{})
+	err = d.settings.checkInvocations(cypher)
+	if err != nil {
+		return response, err
+	}
 
+	parameters, err := toParameters(query)
 	if err != nil {
-		errMsg := "InternalError!"
-		switch err.(type) {
-		default:
-			return response, err
-		case *neo4j.ConnectivityError:
-			errMsg = "ConnectivityError: Can not connect to specified url."
-		}
+		return response, newValidationError(err)
+	}
 
-		log.DefaultLogger.Error(errMsg, ERROR, err.Error())
-		return response, errors.New(errMsg + " Please review log for more details.")
+	txConfig, err := d.transactionConfig(query)
+	if err != nil {
+		return response, newValidationError(err)
 	}
-	// return appropriate format according to the choosen format(nodegraph or table)
+
+	// the options are kept when the format changes, therefore they are only validated for the node graph
 	if query.Format == "nodegraph" {
-		return toGraphResponse(ctx, result)
-	} else {
-		return toDataResponse(ctx, result)
+		err = query.NodeGraph.validate()
+		if err != nil {
+			return response, newValidationError(err)
+		}
 	}
-}
-
-func toDataResponse(ctx context.Context, result neo4j.ResultWithContext) (backend.DataResponse, error) {
-	response := backend.DataResponse{}
 
-	keys, err := result.Keys()
+	session, err := d.newSession(ctx, query.identity)
 	if err != nil {
 		return response, err
 	}
+	defer session.Close(ctx)
 
-	// create data frame response.
-	frame := data.NewFrame("response")
+	// explain does not execute the query, therefore it is not checked
+	if d.settings.StrictReadOnly && query.QueryType != QUERY_TYPE_EXPLAIN {
+		err = checkReadOnly(ctx, session, cypher, parameters, txConfig)
+		if err != nil {
+			return response, classifyError(err)
+		}
+	}
 
-	var allRecords, _ = result.Collect(ctx)
+	cypher = toPlanCypher(cypher, query.QueryType)
+	log.DefaultLogger.Debug("Execute Cypher Query: '"+cypher+"'", DATASOURCE_UID, d.id)
 
-	// infer data type per column and define frame for it
-	for columnNr, columnName := range keys {
-		var typ interface{}
-		if len(allRecords) > 0 {
-			typ = getTypeArray(allRecords[0], columnNr)
-		} else {
-			typ = getTypeArray(nil, columnNr)
+	// the row limit of the query overrides the default of the datasource
+	maxRows := d.settings.MaxRows
+	if query.MaxRows > 0 {
+		maxRows = query.MaxRows
+	}
+
+	var limit *rowLimit
+	var summary neo4j.ResultSummary
+	toResponse := func(result neo4j.ResultWithContext) (backend.DataResponse, error) {
+		// each attempt of a retried transaction starts with a new limit
+		limit = newRowLimit(maxRows)
+		if isPlanQuery(query.QueryType) {
+			summary, err = result.Consume(ctx)
+			if err != nil {
+				return backend.DataResponse{}, err
+			}
+			return toPlanResponse(summary, query.QueryType)
+		}
+
+		response, err := toFormatResponse(ctx, result, limit, query)
+		if err != nil {
+			return response, err
 		}
 
-		if typ == nil {
-			log.DefaultLogger.Debug("Could not infer type from first columnNr, because value was nil. Trying next rows")
+		summary, err = result.Consume(ctx)
+		return response, err
+	}
 
-			for i := 1; i < len(allRecords) && typ == nil; i++ {
-				typ = getTypeArray(allRecords[i], columnNr)
-			}
+	if query.Retry {
+		response, err = d.executeRead(ctx, session, cypher, parameters, txConfig, toResponse)
+	} else {
+		var result neo4j.ResultWithContext
+		result, err = session.Run(ctx, cypher, parameters, txConfig...)
+		if err == nil {
+			response, err = toResponse(result)
 		}
+	}
+
+	if err != nil {
+		return response, classifyError(err)
+	}
 
-		if typ == nil {
-			log.DefaultLogger.Debug("After looking at all rows, type is still nil. Assigning string-type as default")
-			typ = []*string{}
+	setExecutedQueryString(response.Frames, cypher)
+	setResultSummary(response.Frames, summary)
+	if limit.truncated() && len(response.Frames) > 0 {
+		log.DefaultLogger.Debug("Result truncated", DATASOURCE_UID, d.id, "maxRows", limit.max)
+		addNotice(response.Frames[0], limit.notice())
+	}
+	return response, nil
+}
+
+// executes the query in a managed read transaction, which the driver retries on transient
+// errors until the max retry time is exceeded. The result is consumed within the transaction.
+func (d *Neo4JDatasource) executeRead(ctx context.Context, session neo4j.SessionWithContext, cypher string, parameters map[string]interface{},
+	txConfig []func(*neo4j.TransactionConfig), toResponse func(neo4j.ResultWithContext) (backend.DataResponse, error)) (backend.DataResponse, error) {
+	attempts := 0
+	response, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		attempts++
+		if attempts > 1 {
+			log.DefaultLogger.Debug("Retry query", DATASOURCE_UID, d.id, "attempt", attempts)
 		}
 
-		frame.Fields = append(frame.Fields,
-			data.NewField(columnName, nil, typ),
-		)
+		result, err := tx.Run(ctx, cypher, parameters)
+		if err != nil {
+			return nil, err
+		}
+		return toResponse(result)
+	}, txConfig...)
+
+	if err != nil {
+		return backend.DataResponse{}, err
+	}
+	return response.(backend.DataResponse), nil
+}
+
+// return appropriate format according to the choosen format(nodegraph, timeseries, numeric or table)
+func toFormatResponse(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit, query neo4JQuery) (backend.DataResponse, error) {
+	switch query.Format {
+	case "nodegraph":
+		return toGraphResponse(ctx, result, limit, query.NodeGraph)
+	case "timeseries":
+		return toTimeSeriesResponse(ctx, result, limit, query.TimeSeriesType)
+	case "numeric":
+		return toNumericResponse(ctx, result, limit, query.NumericType)
+	default:
+		return toDataResponse(ctx, result, limit)
+	}
+}
+
+func addNotice(frame *data.Frame, notice data.Notice) {
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.Notices = append(frame.Meta.Notices, notice)
+}
+
+// the timeout of the query overrides the default of the datasource.
+// A timeout of 0 means the server side default is used.
+func (d *Neo4JDatasource) queryTimeout(query neo4JQuery) (time.Duration, error) {
+	if query.QueryTimeout != "" {
+		return parseDurationSetting("queryTimeout", query.QueryTimeout)
+	}
+	return parseDurationSetting("queryTimeout", d.settings.QueryTimeout)
+}
+
+// builds the transaction config of the query.
+// The timeout is enforced by the server, which terminates the transaction.
+func (d *Neo4JDatasource) transactionConfig(query neo4JQuery) ([]func(*neo4j.TransactionConfig), error) {
+	var txConfig []func(*neo4j.TransactionConfig)
+
+	timeout, err := d.queryTimeout(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// a timeout of 0 would disable the server side default timeout
+	if timeout > 0 {
+		txConfig = append(txConfig, neo4j.WithTxTimeout(timeout))
 	}
 
-	// iterate through rows and append frame of values to result
-	for _, currentRecord := range allRecords {
-		values := currentRecord.Values
-		vals := make([]interface{}, len(frame.Fields))
-		for col, v := range values {
-			val := toValue(v)
-			vals[col] = val
+	return txConfig, nil
+}
+
+// shows the cypher query after macro expansion in the query inspector
+func setExecutedQueryString(frames data.Frames, cypher string) {
+	for _, frame := range frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
 		}
-		frame.AppendRow(vals...)
+		frame.Meta.ExecutedQueryString = cypher
+	}
+}
+
+func toDataResponse(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit) (backend.DataResponse, error) {
+	response := backend.DataResponse{}
+
+	keys, err := result.Keys()
+	if err != nil {
+		return response, err
+	}
+
+	// stream rows into frame and infer data type per column from the first non nil value
+	builder := newTableFrameBuilder("response", keys, limit)
+	err = forEachRecord(ctx, result, limit, builder.append)
+	if err != nil {
+		return response, err
 	}
 
 	// add the frames to the response.
-	response.Frames = append(response.Frames, frame)
+	response.Frames = append(response.Frames, builder.build())
 	return response, nil
 }
 
-func createGraphDataFrame(name string, typ interface{}, metaFields []string, allRecords []*neo4j.Record) (*data.Frame, map[string]int, int) {
+func createGraphDataFrame(name string, metaFields []string, allProps []map[string]any) (*data.Frame, map[string]int, int) {
 
 	// anonymous function to create dataframe with string fields
 	createStringFieldList := func(fields []string) []*data.Field {
@@ -225,34 +418,11 @@ func createGraphDataFrame(name string, typ interface{}, metaFields []string, all
 	propMap := make(map[string]int)
 	propIndex := len(metaFields)
 
-	for _, currentRecord := range allRecords {
-		values := currentRecord.Values
-		for _, v := range values {
-
-			var isType bool
-			var props map[string]any
-			switch typ.(type) {
-			default:
-				continue
-			case dbtype.Node:
-				_, isType = v.(dbtype.Node)
-				if isType {
-					props = v.(dbtype.Node).Props
-				}
-			case dbtype.Relationship:
-				_, isType = v.(dbtype.Relationship)
-				if isType {
-					props = v.(dbtype.Relationship).Props
-				}
-			}
-
-			if isType {
-				for name := range props {
-					// check if prop already exists.
-					if _, exists := propMap[name]; !exists {
-						propMap[name] = 0
-					}
-				}
+	for _, props := range allProps {
+		for name := range props {
+			// check if prop already exists.
+			if _, exists := propMap[name]; !exists {
+				propMap[name] = 0
 			}
 		}
 	}
@@ -275,7 +445,7 @@ func createGraphDataFrame(name string, typ interface{}, metaFields []string, all
 }
 
 // Return customized response for node graph panel
-func toGraphResponse(ctx context.Context, result neo4j.ResultWithContext) (backend.DataResponse, error) {
+func toGraphResponse(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit, options nodeGraphOptions) (backend.DataResponse, error) {
 	response := backend.DataResponse{}
 
 	// Check if query has any keys.
@@ -284,80 +454,78 @@ func toGraphResponse(ctx context.Context, result neo4j.ResultWithContext) (backe
 		return response, err
 	}
 
-	var allRecords, _ = result.Collect(ctx)
-
-	// a map of Id to empty string to prevent insert duplicate nodes in the dataframe
-	nodeIdMap := make(map[string]string)
+	// only nodes and relationships are kept in memory, all other values are dropped while streaming
+	graph := newGraphCollector(limit.max)
+	err = forEachRecord(ctx, result, limit, func(record *neo4j.Record) {
+		for _, v := range record.Values {
+			graph.add(v)
+		}
+	})
+	if err != nil {
+		return response, err
+	}
 
 	// https://grafana.com/docs/grafana/latest/panels-visualizations/visualizations/node-graph/#nodes-data-frame-structure
-	nodesFrame, nodesPropMap, nodesRowLen := createGraphDataFrame("nodes", dbtype.Node{}, []string{"id", "title", "detail__labels"}, allRecords)
+	nodesFrame, nodesPropMap, nodesRowLen := createGraphDataFrame("nodes", []string{"id", "title", "detail__labels"}, graph.nodeProps())
 
 	// https://grafana.com/docs/grafana/latest/panels-visualizations/visualizations/node-graph/#edges-data-frame-structure
-	edgesFrame, edgesPropMap, edgesRowLen := createGraphDataFrame("edges", dbtype.Relationship{}, []string{"id", "source", "target", "mainStat"}, allRecords)
-
-	// iterate through rows and append nodes to frame
-	for _, currentRecord := range allRecords {
-		values := currentRecord.Values
-		for _, v := range values {
-			node, isNode := v.(dbtype.Node)
-			if isNode {
-				// check if this Node was already added.
-				if _, exists := nodeIdMap[node.ElementId]; !exists {
-
-					firstLabel := ""
-					if len(node.Labels) > 0 {
-						firstLabel = node.Labels[0]
-					}
-
-					nodeIdMap[node.ElementId] = ""
-
-					row := make([]interface{}, nodesRowLen)
-					row[0] = &node.ElementId
-					row[1] = &firstLabel
-					if len(node.Labels) > 1 {
-						row[2] = asJson(node.Labels)
-					}
-					for name, value := range node.Props {
-						propIndex := nodesPropMap[name]
-						row[propIndex] = asJson(value)
-					}
-
-					nodesFrame.AppendRow(row...)
-				}
-			}
+	edgesFrame, edgesPropMap, edgesRowLen := createGraphDataFrame("edges", []string{"id", "source", "target", "mainStat"}, graph.edgeProps())
+
+	// append nodes to frame
+	for _, node := range graph.nodes {
+		firstLabel := ""
+		if len(node.Labels) > 0 {
+			firstLabel = node.Labels[0]
+		}
+
+		row := make([]interface{}, nodesRowLen)
+		row[0] = ptr(node.ElementId)
+		row[1] = &firstLabel
+		if len(node.Labels) > 1 {
+			row[2] = asJson(node.Labels)
+		}
+		for name, value := range node.Props {
+			propIndex := nodesPropMap[name]
+			row[propIndex] = asJson(value)
 		}
+
+		nodesFrame.AppendRow(row...)
 	}
 
-	// iterate through rows and append edges to frame
-	for _, currentRecord := range allRecords {
-		values := currentRecord.Values
-		for _, v := range values {
-			edge, isEdge := v.(dbtype.Relationship)
-
-			// check if Start and End ElementId exists.
-			_, startExists := nodeIdMap[edge.StartElementId]
-			_, endExists := nodeIdMap[edge.EndElementId]
-
-			if isEdge && startExists && endExists {
-				row := make([]interface{}, edgesRowLen)
-				row[0] = &edge.ElementId
-				row[1] = &edge.StartElementId
-				row[2] = &edge.EndElementId
-				row[3] = &edge.Type
-				for name, value := range edge.Props {
-					propIndex := edgesPropMap[name]
-					row[propIndex] = asJson(value)
-				}
-
-				edgesFrame.AppendRow(row...)
-			}
+	// only edges between nodes of the graph are shown
+	var edges []dbtype.Relationship
+	for _, edge := range graph.edges {
+		// check if Start and End ElementId exists.
+		if graph.nodeIds[edge.StartElementId] && graph.nodeIds[edge.EndElementId] {
+			edges = append(edges, edge)
 		}
 	}
 
+	// append edges to frame
+	for _, edge := range edges {
+		row := make([]interface{}, edgesRowLen)
+		row[0] = ptr(edge.ElementId)
+		row[1] = ptr(edge.StartElementId)
+		row[2] = ptr(edge.EndElementId)
+		row[3] = ptr(edge.Type)
+		for name, value := range edge.Props {
+			propIndex := edgesPropMap[name]
+			row[propIndex] = asJson(value)
+		}
+
+		edgesFrame.AppendRow(row...)
+	}
+
+	options.applyToNodes(nodesFrame, graph.nodes)
+	options.applyToEdges(edgesFrame, edges)
+
 	// Set Preffered Visualization to nodegraph for both data frames
 	m := data.FrameMeta{PreferredVisualization: "nodeGraph"}
 	nodesFrame = nodesFrame.SetMeta(&m)
 	edgesFrame = edgesFrame.SetMeta(&m)
+	if graph.truncated {
+		addNotice(nodesFrame, graph.notice())
+	}
 
 	// add the frames to the response.
 	response.Frames = append(response.Frames, nodesFrame, edgesFrame)
@@ -369,77 +537,71 @@ func toGraphResponse(ctx context.Context, result neo4j.ResultWithContext) (backe
 // datasource configuration page which allows users to verify that
 // a datasource is working as expected.
 func (d *Neo4JDatasource) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
-	return d.checkHealth(ctx)
+	identity := sessionIdentity{user: req.PluginContext.User}
+	if d.settings.OAuthPassThru {
+		identity.authorization = req.GetHTTPHeader(backend.OAuthIdentityTokenHeaderName)
+	}
+	return d.checkHealth(ctx, identity)
 }
 
-func (d *Neo4JDatasource) checkHealth(ctx context.Context) (*backend.CheckHealthResult, error) {
+func (d *Neo4JDatasource) checkHealth(ctx context.Context, identity sessionIdentity) (*backend.CheckHealthResult, error) {
 	log.DefaultLogger.Debug("CheckHealth called", DATASOURCE_UID, d.id)
 
 	err := d.driver.VerifyConnectivity(ctx)
 
 	// Some errs are not tackled by VerifyConnectivity
+	details := healthDetails{}
 	if err == nil {
 		neo4JQuery := neo4JQuery{
-			CypherQuery: "Match(a) return a limit 1",
+			CypherQuery: HEALTH_QUERY,
+			identity:    identity,
 		}
 
+		start := time.Now()
 		_, err = d.query(ctx, neo4JQuery)
+		details.LatencyMs = time.Since(start).Milliseconds()
 	}
 
 	if err != nil {
+		message := err.Error()
+		if tlsMessage, isTlsError := describeTlsError(err); isTlsError {
+			message = tlsMessage
+		}
+
 		return &backend.CheckHealthResult{
 			Status:  backend.HealthStatusError,
-			Message: err.Error(),
+			Message: message,
 		}, nil
 	}
 
-	return &backend.CheckHealthResult{
-		Status:  backend.HealthStatusOk,
-		Message: "Data source is working",
-	}, nil
-}
-
-func unmarshalDataSourceSettings(dSIset backend.DataSourceInstanceSettings) (neo4JSettings, error) {
-	// Unmarshal the JSON into our settings Model.
-	var neo4JSettings neo4JSettings
-	err := json.Unmarshal(dSIset.JSONData, &neo4JSettings)
+	d.readHealthDetails(ctx, identity, &details)
+	jsonDetails, err := json.Marshal(details)
 	if err != nil {
-		return neo4JSettings, err
-
-	}
-
-	if decryptedPassword, exists := dSIset.DecryptedSecureJSONData["password"]; exists {
-		neo4JSettings.Password = decryptedPassword
-	}
-
-	return neo4JSettings, nil
-}
-
-func getTypeArray(record *neo4j.Record, idx int) interface{} {
-	if record == nil {
-		return []*string{}
+		return nil, err
 	}
 
-	typ := record.Values[idx]
-
-	return getTypeArrayByVal(typ)
+	return &backend.CheckHealthResult{
+		Status:      backend.HealthStatusOk,
+		Message:     "Data source is working: " + details.summary(),
+		JSONDetails: jsonDetails,
+	}, nil
 }
 
 // https://github.com/neo4j/neo4j-go-driver#value-types
-func getTypeArrayByVal(typ any) interface{} {
+func getTypeArrayByVal(typ any, capacity int) interface{} {
 	switch typ.(type) {
 	case int64:
-		return []*int64{}
+		return make([]*int64, 0, capacity)
 	case float64:
-		return []*float64{}
+		return make([]*float64, 0, capacity)
 	case bool:
-		return []*bool{}
+		return make([]*bool, 0, capacity)
 	case time.Time, dbtype.Date, dbtype.Time, dbtype.LocalTime, dbtype.LocalDateTime:
-		return []*time.Time{}
+		return make([]*time.Time, 0, capacity)
 	case nil:
 		return nil
 	default:
-		return []*string{}
+		return make([]*string, 0, capacity)
 	}
 }
 
@@ -479,6 +641,10 @@ func toValue(val interface{}) interface{} {
 	}
 }
 
+func ptr[T any](val T) *T {
+	return &val
+}
+
 func asJson(val interface{}) *string {
 	r, err := json.Marshal(val)
 	if err != nil {
@@ -504,13 +670,29 @@ type neo4JQuery struct {
 	// TimeRange is the Start and End of the query as sent by the frontend.
 	TimeRange backend.TimeRange
 
-	CypherQuery string `json:"cypherQuery"`
-	Format      string `json:"Format"`
-}
+	CypherQuery string                    `json:"cypherQuery"`
+	Format      string                    `json:"Format"`
+	Parameters  map[string]neo4JParameter `json:"parameters"`
+
+	// identity of the user, who executes the query
+	identity sessionIdentity
+
+	// TimeSeriesType defines whether the time series format returns a wide frame or multiple frames.
+	TimeSeriesType string `json:"timeSeriesType"`
+
+	// NumericType defines whether the numeric format returns a long frame or multiple frames.
+	NumericType string `json:"numericType"`
+
+	// MaxRows overrides the maximum number of records of the datasource settings.
+	MaxRows int `json:"maxRows"`
+
+	// QueryTimeout overrides the query timeout of the datasource settings, e.g. 30s.
+	QueryTimeout string `json:"queryTimeout"`
+
+	// Retry executes the query in a managed transaction, which is retried on transient errors.
+	// Not supported for CALL {} IN TRANSACTIONS, which requires an auto commit transaction.
+	Retry bool `json:"retry"`
 
-type neo4JSettings struct {
-	Url      string `json:"url"`
-	Database string `json:"database"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	// NodeGraph maps properties and labels onto the fields of the node graph format.
+	NodeGraph nodeGraphOptions `json:"nodeGraph"`
 }
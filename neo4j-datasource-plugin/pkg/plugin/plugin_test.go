@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 //ExampleTest: https://github.com/grafana/grafana-plugin-sdk-go/blob/main/data/frame_test.go
@@ -49,7 +50,7 @@ func TestHealthcheckIsErrorDueToDeserialize(t *testing.T) {
 	settings := backend.DataSourceInstanceSettings{}
 	settings.JSONData = []byte{1}
 
-	_, err := NewNeo4JDatasource(settings)
+	_, err := NewNeo4JDatasource(context.Background(), settings)
 
 	expectedMsg := "can not deserialize DataSource settings"
 	if !strings.Contains(err.Error(), expectedMsg) {
@@ -135,13 +136,13 @@ func testCheckHealthAndMessage(t *testing.T, neo4JSettings neo4JSettings, expect
 
 func testCheckHealthAndMessageWithSettings(t *testing.T, settings backend.DataSourceInstanceSettings, expectedStatus backend.HealthStatus, expectedMessagePart string) {
 
-	instance, err := NewNeo4JDatasource(settings)
+	instance, err := NewNeo4JDatasource(context.Background(), settings)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	neo4JDatasource := instance.(*Neo4JDatasource)
-	res, err := neo4JDatasource.checkHealth(context.Background())
+	res, err := neo4JDatasource.checkHealth(context.Background(), sessionIdentity{})
 
 	if err != nil {
 		t.Fatal(err)
@@ -158,6 +159,100 @@ func testCheckHealthAndMessageWithSettings(t *testing.T, settings backend.DataSo
 	fmt.Println("Message:" + res.Message)
 }
 
+// driver which panics when a session is created
+type panickingDriver struct {
+	neo4j.DriverWithContext
+}
+
+func (d panickingDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	panic("session failed")
+}
+
+func TestQueryDataIsolatesFailingQueries(t *testing.T) {
+	// every query which reaches the database panics
+	neo4JDatasource := &Neo4JDatasource{driver: panickingDriver{}, querySlots: make(chan struct{}, 2)}
+
+	req := &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"cypherQuery": `)},
+			{RefID: "B", JSON: []byte(`{"cypherQuery": "return 1"}`)},
+			{RefID: "C", JSON: []byte(`{"cypherQuery": "return $__doesNotExist()"}`)},
+		},
+	}
+
+	res, err := neo4JDatasource.QueryData(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedErrors := map[string]string{
+		"A": "unexpected end of JSON input",
+		"B": "Query B failed unexpectedly",
+		"C": "Unknown macro '$__doesNotExist'",
+	}
+
+	if len(res.Responses) != len(expectedErrors) {
+		t.Fatalf("Expected %d responses, but was %d", len(expectedErrors), len(res.Responses))
+	}
+
+	for refID, expectedError := range expectedErrors {
+		resErr := res.Responses[refID].Error
+		if resErr == nil || !strings.Contains(resErr.Error(), expectedError) {
+			t.Errorf("Expected error of %s containing %s, but was %v", refID, expectedError, resErr)
+		}
+	}
+
+	expectedStatus := map[string]backend.Status{
+		"A": backend.StatusBadRequest,
+		"B": backend.StatusInternal,
+		"C": backend.StatusBadRequest,
+	}
+
+	for refID, status := range expectedStatus {
+		if res.Responses[refID].Status != status {
+			t.Errorf("Expected status of %s %d, but was %d", refID, status, res.Responses[refID].Status)
+		}
+	}
+
+	expectedSource := map[string]backend.ErrorSource{
+		"A": backend.ErrorSourceDownstream,
+		"B": backend.ErrorSourcePlugin,
+		"C": backend.ErrorSourceDownstream,
+	}
+
+	for refID, source := range expectedSource {
+		if res.Responses[refID].ErrorSource != source {
+			t.Errorf("Expected error source of %s %s, but was %s", refID, source, res.Responses[refID].ErrorSource)
+		}
+	}
+}
+
+func TestQueryDataWithCancelledContext(t *testing.T) {
+	// no free query slot, therefore the query waits until the context is cancelled
+	neo4JDatasource := &Neo4JDatasource{querySlots: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(`{"cypherQuery": "return 1"}`)}},
+	}
+
+	res, err := neo4JDatasource.QueryData(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := res.Responses["A"]
+	if response.Error == nil || !strings.Contains(response.Error.Error(), "Query was cancelled") {
+		t.Error("Expected cancelled query, but was", response.Error)
+	}
+
+	if response.Status != backend.StatusBadRequest || response.ErrorSource != backend.ErrorSourceDownstream {
+		t.Errorf("Expected status %d and source downstream, but was %d and %s", backend.StatusBadRequest, response.Status, response.ErrorSource)
+	}
+}
+
 func TestNoRows(t *testing.T) {
 	skipIfIsShort(t)
 	expectedFrame := data.NewFrame("response",
@@ -169,6 +264,21 @@ func TestNoRows(t *testing.T) {
 	runNeo4JIntegrationTableTest(t, cypher, expectedFrame)
 }
 
+func TestQueryWithRetry(t *testing.T) {
+	skipIfIsShort(t)
+	expectedFrame := data.NewFrame("response",
+		data.NewField("A", nil, []*int64{ptrI(1), ptrI(2)}),
+	)
+
+	neo4JQuery := neo4JQuery{
+		CypherQuery: "UNWIND [1, 2] AS A RETURN A",
+		Format:      "table",
+		Retry:       true,
+	}
+
+	runNeo4JIntegrationTableQueryTest(t, neo4JQuery, expectedFrame)
+}
+
 func TestStringColumn(t *testing.T) {
 	skipIfIsShort(t)
 	expectedFrame := data.NewFrame("response",
@@ -509,15 +619,33 @@ func TestGraphFormat(t *testing.T) {
 		}),
 	)
 
-	m := data.FrameMeta{PreferredVisualization: "nodeGraph"}
+	cypher := "Match(m:Movie)<-[a:ACTED_IN]-(f:Person) where m.title = 'The Matrix' and f.name = 'Keanu Reeves' return  m.title, f, a, m order by m.title"
+
+	m := data.FrameMeta{PreferredVisualization: "nodeGraph", ExecutedQueryString: cypher}
 	expectedNodesFrame = expectedNodesFrame.SetMeta(&m)
 	expectedEdgesFrame = expectedEdgesFrame.SetMeta(&m)
 
-	cypher := "Match(m:Movie)<-[a:ACTED_IN]-(f:Person) where m.title = 'The Matrix' and f.name = 'Keanu Reeves' return  m.title, f, a, m order by m.title"
-
 	runNeo4JIntegrationGraphTest(t, cypher, expectedNodesFrame, expectedEdgesFrame)
 }
 
+func TestGraphFormatWithPath(t *testing.T) {
+	skipIfIsShort(t)
+
+	cypher := "MATCH p=(f:Person)-[:ACTED_IN]->(m:Movie) WHERE m.title = 'The Matrix' AND f.name = 'Keanu Reeves' RETURN p, nodes(p) AS n"
+	res := runNeo4JIntegrationTest(t, cypher, "nodegraph")
+	if len(res.Frames) != 2 {
+		t.Fatal("Frames len is not 2")
+	}
+
+	if res.Frames[0].Rows() != 2 {
+		t.Error("Expected the 2 distinct nodes of the path, but was", res.Frames[0].Rows())
+	}
+
+	if res.Frames[1].Rows() != 1 {
+		t.Error("Expected the relationship of the path, but was", res.Frames[1].Rows())
+	}
+}
+
 func runNeo4JIntegrationGraphTest(t *testing.T, cypher string, expectedNodes *data.Frame, expectedEdges *data.Frame) {
 	res := runNeo4JIntegrationTest(t, cypher, "nodegraph")
 	if len(res.Frames) != 2 {
@@ -552,12 +680,26 @@ func runNeo4JIntegrationGraphTest(t *testing.T, cypher string, expectedNodes *da
 }
 
 func runNeo4JIntegrationTableTest(t *testing.T, cypher string, expected *data.Frame) {
-	res := runNeo4JIntegrationTest(t, cypher, "table")
+	neo4JQuery := neo4JQuery{
+		CypherQuery: cypher,
+		Format:      "table",
+	}
+
+	runNeo4JIntegrationTableQueryTest(t, neo4JQuery, expected)
+}
+
+func runNeo4JIntegrationTableQueryTest(t *testing.T, neo4JQuery neo4JQuery, expected *data.Frame) {
+	res := runNeo4JIntegrationQuery(t, neo4JQuery)
 
 	if len(res.Frames) != 1 {
 		t.Fatal("Frames len is not 1")
 	}
 
+	// without macros the executed query is the same as the given query
+	if expected.Meta == nil {
+		expected.SetMeta(&data.FrameMeta{ExecutedQueryString: neo4JQuery.CypherQuery})
+	}
+
 	frame := res.Frames[0]
 
 	expectedAsTable, _ := expected.StringTable(-1, -1)
@@ -573,6 +715,15 @@ func runNeo4JIntegrationTableTest(t *testing.T, cypher string, expected *data.Fr
 }
 
 func runNeo4JIntegrationTest(t *testing.T, cypher string, format string) backend.DataResponse {
+	neo4JQuery := neo4JQuery{
+		CypherQuery: cypher,
+		Format:      format,
+	}
+
+	return runNeo4JIntegrationQuery(t, neo4JQuery)
+}
+
+func runNeo4JIntegrationQuery(t *testing.T, neo4JQuery neo4JQuery) backend.DataResponse {
 	neo4JSettings := neo4JSettings{
 		Url:      "neo4j://localhost:7687",
 		Database: "",
@@ -580,26 +731,37 @@ func runNeo4JIntegrationTest(t *testing.T, cypher string, format string) backend
 		Password: "Password123",
 	}
 
-	neo4JQuery := neo4JQuery{
-		CypherQuery: cypher,
-		Format:      format,
+	res, err := runNeo4JIntegrationQueryWithSettings(t, neo4JSettings, neo4JQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Error != nil {
+		t.Error(res.Error)
+	}
+
+	// the result summary contains timings, which differ per execution
+	for _, frame := range res.Frames {
+		if frame.Meta != nil {
+			frame.Meta.Stats = nil
+			frame.Meta.Custom = nil
+		}
 	}
+	return res
+}
 
+func runNeo4JIntegrationQueryWithSettings(t *testing.T, neo4JSettings neo4JSettings, neo4JQuery neo4JQuery) (backend.DataResponse, error) {
 	settings := backend.DataSourceInstanceSettings{}
 	settings.JSONData = asJsonBytes(t, neo4JSettings)
 
-	instance, _ := NewNeo4JDatasource(settings)
-	neo4JDatasource := instance.(*Neo4JDatasource)
-
-	res, err := neo4JDatasource.query(context.Background(), neo4JQuery)
+	instance, err := NewNeo4JDatasource(context.Background(), settings)
 	if err != nil {
 		t.Fatal(err)
 	}
+	neo4JDatasource := instance.(*Neo4JDatasource)
+	defer neo4JDatasource.Dispose()
 
-	if res.Error != nil {
-		t.Error(res.Error)
-	}
-	return res
+	return neo4JDatasource.query(context.Background(), neo4JQuery)
 }
 
 func asJsonBytes(t *testing.T, obj interface{}) []byte {
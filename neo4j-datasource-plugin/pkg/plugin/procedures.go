@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// kinds of invocations, which are checked against the allow and deny lists
+const (
+	INVOCATION_PROCEDURE string = "procedure"
+	INVOCATION_FUNCTION  string = "function"
+)
+
+// a procedure or function invoked by a cypher query
+type invocation struct {
+	kind string
+	name string
+}
+
+// a token of a cypher query, quoted is true for backtick quoted names
+type cypherToken struct {
+	text   string
+	quoted bool
+}
+
+func (t cypherToken) isName() bool {
+	return t.quoted || (t.text != "" && isNameRune(rune(t.text[0])))
+}
+
+func (t cypherToken) isKeyword(keyword string) bool {
+	return !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// splits the cypher query into names and symbols.
+// Whitespace, comments and string literals are dropped.
+func tokenizeCypher(cypher string) []cypherToken {
+	var tokens []cypherToken
+	runes := []rune(cypher)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case c == '\'' || c == '"':
+			i++
+			for i < len(runes) && runes[i] != c {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '`':
+			// a backtick within a quoted name is escaped by doubling it
+			var name strings.Builder
+			for i++; i < len(runes); i++ {
+				if runes[i] == '`' {
+					if i+1 < len(runes) && runes[i+1] == '`' {
+						name.WriteRune('`')
+						i++
+						continue
+					}
+					break
+				}
+				name.WriteRune(runes[i])
+			}
+			i++
+			tokens = append(tokens, cypherToken{text: name.String(), quoted: true})
+		case isNameRune(c):
+			start := i
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, cypherToken{text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, cypherToken{text: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// finds the procedures and the namespaced functions, like apoc.text.join, invoked by the cypher query.
+// Functions without namespace, like count, are built in and not returned.
+func findInvocations(cypher string) []invocation {
+	var invocations []invocation
+	tokens := tokenizeCypher(cypher)
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].isName() {
+			continue
+		}
+
+		// a name is a sequence of dot separated parts, e.g. db.labels
+		start := i
+		parts := []string{tokens[i].text}
+		for i+2 < len(tokens) && tokens[i+1].text == "." && !tokens[i+1].quoted && tokens[i+2].isName() {
+			parts = append(parts, tokens[i+2].text)
+			i += 2
+		}
+
+		// parameters like $label are no invocations
+		if start > 0 && tokens[start-1].text == "$" && !tokens[start-1].quoted {
+			continue
+		}
+
+		name := strings.Join(parts, ".")
+		switch {
+		case start > 0 && tokens[start-1].isKeyword("CALL"):
+			invocations = append(invocations, invocation{kind: INVOCATION_PROCEDURE, name: name})
+		case len(parts) > 1 && i+1 < len(tokens) && tokens[i+1].text == "(" && !tokens[i+1].quoted:
+			invocations = append(invocations, invocation{kind: INVOCATION_FUNCTION, name: name})
+		}
+	}
+
+	return invocations
+}
+
+// checks whether the name matches one of the glob patterns, ignoring case
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		matched, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(name))
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePatterns(name string, patterns []string) error {
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("Invalid setting %s: patterns must not be empty", name)
+		}
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("Invalid setting %s: pattern '%s' is invalid", name, pattern)
+		}
+	}
+	return nil
+}
+
+// rejects the query, if it invokes a procedure or function which matches the deny list,
+// or which does not match the allow list, if an allow list is configured
+func (s neo4JSettings) checkInvocations(cypher string) error {
+	if len(s.ProcedureAllowList) == 0 && len(s.ProcedureDenyList) == 0 {
+		return nil
+	}
+
+	for _, inv := range findInvocations(cypher) {
+		denied := matchesAny(s.ProcedureDenyList, inv.name)
+		if denied || (len(s.ProcedureAllowList) > 0 && !matchesAny(s.ProcedureAllowList, inv.name)) {
+			message := fmt.Sprintf("Query was rejected, because the %s '%s' is not allowed by the datasource settings.", inv.kind, inv.name)
+			return &queryError{
+				message: message,
+				status:  backend.StatusForbidden,
+				source:  backend.ErrorSourceDownstream,
+				err:     fmt.Errorf("%s %s is not allowed", inv.kind, inv.name),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func TestFindInvocations(t *testing.T) {
+	tests := []struct {
+		cypher   string
+		expected []invocation
+	}{
+		{"MATCH (n) RETURN count(n), toUpper(n.name)", nil},
+		{"CALL db.labels() YIELD label RETURN label", []invocation{{INVOCATION_PROCEDURE, "db.labels"}}},
+		{"call dbms.listConfig", []invocation{{INVOCATION_PROCEDURE, "dbms.listConfig"}}},
+		{"CALL `dbms`.`listConfig`()", []invocation{{INVOCATION_PROCEDURE, "dbms.listConfig"}}},
+		{"CALL apoc . load . json($url)", []invocation{{INVOCATION_PROCEDURE, "apoc.load.json"}}},
+		{"RETURN apoc.text.join(['a'], ',') AS s, n.name", []invocation{{INVOCATION_FUNCTION, "apoc.text.join"}}},
+		{"CALL { MATCH (n) RETURN n } RETURN n", nil},
+		{"RETURN 'CALL dbms.listConfig()' AS s, \"apoc.load.json(\" AS t", nil},
+		{"RETURN 'it\\'s' AS s // CALL dbms.listConfig()\n/* apoc.load.json() */", nil},
+		{"MATCH (n) WHERE n.ts > $__from.x RETURN n", nil},
+		{
+			"MATCH (n) WITH date.truncate('day', n.ts) AS d CALL apoc.util.sleep(1) RETURN d",
+			[]invocation{{INVOCATION_FUNCTION, "date.truncate"}, {INVOCATION_PROCEDURE, "apoc.util.sleep"}},
+		},
+	}
+
+	for _, test := range tests {
+		diff := cmp.Diff(test.expected, findInvocations(test.cypher), cmp.AllowUnexported(invocation{}))
+		if diff != "" {
+			t.Errorf("%s: %s", test.cypher, diff)
+		}
+	}
+}
+
+func TestCheckInvocations(t *testing.T) {
+	settings := neo4JSettings{
+		ProcedureAllowList: []string{"db.*", "apoc.*"},
+		ProcedureDenyList:  []string{"apoc.load.*"},
+	}
+
+	tests := []struct {
+		cypher          string
+		expectedMessage string
+	}{
+		{"CALL db.labels()", ""},
+		{"RETURN APOC.TEXT.JOIN(['a'], ',')", ""},
+		{"CALL apoc.load.json('file:///etc/passwd')", "procedure 'apoc.load.json' is not allowed"},
+		{"CALL dbms.listConfig()", "procedure 'dbms.listConfig' is not allowed"},
+		{"RETURN point.distance(a, b)", "function 'point.distance' is not allowed"},
+	}
+
+	for _, test := range tests {
+		err := settings.checkInvocations(test.cypher)
+		if test.expectedMessage == "" {
+			if err != nil {
+				t.Errorf("Expected %s to be allowed, but was %v", test.cypher, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error containing %s, but was %v", test.expectedMessage, err)
+			continue
+		}
+
+		if classifyError(err).status != backend.StatusForbidden {
+			t.Error("Expected status forbidden, but was", classifyError(err).status)
+		}
+	}
+}
+
+func TestCheckInvocationsWithoutLists(t *testing.T) {
+	err := neo4JSettings{}.checkInvocations("CALL dbms.listConfig()")
+	if err != nil {
+		t.Error("Expected all procedures to be allowed without lists, but was", err)
+	}
+}
@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// explains the query without executing it and rejects the query,
+// if the planner does not classify it as read only
+func checkReadOnly(ctx context.Context, session neo4j.SessionWithContext, cypher string, parameters map[string]interface{}, txConfig []func(*neo4j.TransactionConfig)) error {
+	result, err := session.Run(ctx, "EXPLAIN "+cypher, parameters, txConfig...)
+	if err != nil {
+		return err
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return err
+	}
+
+	return readOnlyError(summary.StatementType())
+}
+
+func readOnlyError(statementType neo4j.StatementType) error {
+	if statementType == neo4j.StatementTypeReadOnly {
+		return nil
+	}
+
+	queryType, exists := statementTypeNames[statementType]
+	if !exists {
+		queryType = "unknown"
+	}
+
+	message := fmt.Sprintf("Query was rejected, because the datasource is strict read-only and the query type is %s. Only read queries are allowed.", queryType)
+	return &queryError{
+		message: message,
+		status:  backend.StatusForbidden,
+		source:  backend.ErrorSourceDownstream,
+		err:     errors.New(message),
+	}
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestReadOnlyError(t *testing.T) {
+	if err := readOnlyError(neo4j.StatementTypeReadOnly); err != nil {
+		t.Error("Expected read query to be allowed, but was", err)
+	}
+
+	tests := []struct {
+		statementType neo4j.StatementType
+		expectedType  string
+	}{
+		{neo4j.StatementTypeReadWrite, "read-write"},
+		{neo4j.StatementTypeWriteOnly, "write"},
+		{neo4j.StatementTypeSchemaWrite, "schema-write"},
+		{neo4j.StatementTypeUnknown, "unknown"},
+	}
+
+	for _, test := range tests {
+		qErr := classifyError(readOnlyError(test.statementType))
+		if qErr.status != backend.StatusForbidden || !strings.Contains(qErr.Error(), "query type is "+test.expectedType) {
+			t.Errorf("Expected rejection of %s query, but was %v", test.expectedType, qErr)
+		}
+	}
+}
+
+func TestStrictReadOnlyIntegration(t *testing.T) {
+	skipIfIsShort(t)
+
+	settings := neo4JSettings{
+		Url:            "neo4j://localhost:7687",
+		Username:       "neo4j",
+		Password:       "Password123",
+		StrictReadOnly: true,
+	}
+
+	_, err := runNeo4JIntegrationQueryWithSettings(t, settings, neo4JQuery{CypherQuery: "CREATE (n:StrictReadOnly) RETURN n"})
+	if err == nil || !strings.Contains(err.Error(), "strict read-only") {
+		t.Error("Expected rejection of write query, but was", err)
+	}
+
+	res, err := runNeo4JIntegrationQueryWithSettings(t, settings, neo4JQuery{CypherQuery: "MATCH (n:StrictReadOnly) RETURN count(n) AS c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := res.Frames[0].Fields[0].At(0).(*int64); *c != 0 {
+		t.Error("Expected no node created by the rejected query, but was", *c)
+	}
+}
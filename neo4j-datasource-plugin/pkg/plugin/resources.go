@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// how long schema information is cached per datasource instance
+const SCHEMA_CACHE_TTL time.Duration = 5 * time.Minute
+
+// cypher queries which return the schema information for the resource endpoints.
+// Each query returns a single string column.
+const (
+	LABELS_QUERY             string = "CALL db.labels() YIELD label RETURN label ORDER BY label"
+	RELATIONSHIP_TYPES_QUERY string = "CALL db.relationshipTypes() YIELD relationshipType RETURN relationshipType ORDER BY relationshipType"
+	PROPERTY_KEYS_QUERY      string = "CALL db.propertyKeys() YIELD propertyKey RETURN propertyKey ORDER BY propertyKey"
+	LABEL_PROPERTIES_QUERY   string = "CALL db.schema.nodeTypeProperties() YIELD nodeLabels, propertyName WITH nodeLabels, propertyName WHERE $label IN nodeLabels AND propertyName IS NOT NULL RETURN DISTINCT propertyName ORDER BY propertyName"
+	PROCEDURES_QUERY         string = "SHOW PROCEDURES YIELD name RETURN name ORDER BY name"
+	FUNCTIONS_QUERY          string = "SHOW FUNCTIONS YIELD name RETURN name ORDER BY name"
+)
+
+// creates the handler for the resource endpoints, which provide schema information for the query editor
+func newResourceHandler(d *Neo4JDatasource) backend.CallResourceHandler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/labels", d.schemaHandler(LABELS_QUERY))
+	mux.HandleFunc("/labels/", d.handleLabelProperties)
+	mux.HandleFunc("/relationship-types", d.schemaHandler(RELATIONSHIP_TYPES_QUERY))
+	mux.HandleFunc("/property-keys", d.schemaHandler(PROPERTY_KEYS_QUERY))
+	mux.HandleFunc("/procedures", d.schemaHandler(PROCEDURES_QUERY))
+	mux.HandleFunc("/functions", d.schemaHandler(FUNCTIONS_QUERY))
+	return httpadapter.New(mux)
+}
+
+// CallResource handles requests to the resource endpoints of the datasource.
+func (d *Neo4JDatasource) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	log.DefaultLogger.Debug("CallResource called", DATASOURCE_UID, d.id, "path", req.Path)
+	return d.resourceHandler.CallResource(ctx, req, sender)
+}
+
+func (d *Neo4JDatasource) schemaHandler(cypher string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.writeSchema(w, r, r.URL.Path, cypher, nil)
+	}
+}
+
+// handles /labels/{label}/properties
+func (d *Neo4JDatasource) handleLabelProperties(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/labels/"), "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] != "properties" {
+		http.NotFound(w, r)
+		return
+	}
+
+	label, err := url.PathUnescape(segments[0])
+	if err != nil {
+		http.Error(w, "Invalid label", http.StatusBadRequest)
+		return
+	}
+
+	d.writeSchema(w, r, "/labels/"+label+"/properties", LABEL_PROPERTIES_QUERY, map[string]any{"label": label})
+}
+
+func (d *Neo4JDatasource) writeSchema(w http.ResponseWriter, r *http.Request, key string, cypher string, parameters map[string]any) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identity := sessionIdentity{user: httpadapter.UserFromContext(r.Context())}
+	if d.settings.OAuthPassThru {
+		identity.authorization = r.Header.Get(backend.OAuthIdentityTokenHeaderName)
+	}
+
+	cacheKey := d.schemaCacheKey(identity, key)
+	values, exists := d.schemaCache.get(cacheKey)
+	if !exists {
+		var err error
+		values, err = d.readStrings(r.Context(), identity, cypher, parameters)
+		if err != nil {
+			qErr := classifyError(err)
+			log.DefaultLogger.Error("Reading schema failed", DATASOURCE_UID, d.id, "path", key, ERROR, err.Error(), "errorSource", qErr.source)
+			http.Error(w, qErr.Error(), int(qErr.status))
+			return
+		}
+		d.schemaCache.set(cacheKey, values)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(values)
+	if err != nil {
+		log.DefaultLogger.Error("Writing schema failed", DATASOURCE_UID, d.id, ERROR, err.Error())
+	}
+}
+
+// executes the cypher query and returns the first column of all records as strings
+func (d *Neo4JDatasource) readStrings(ctx context.Context, identity sessionIdentity, cypher string, parameters map[string]any) ([]string, error) {
+	session, err := d.newSession(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, cypher, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	err = forEachRecord(ctx, result, newRowLimit(0), func(record *neo4j.Record) {
+		if len(record.Values) > 0 && record.Values[0] != nil {
+			values = append(values, fmt.Sprint(record.Values[0]))
+		}
+	})
+	return values, err
+}
+
+// caches schema information with a time to live
+type schemaCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]schemaCacheEntry
+}
+
+type schemaCacheEntry struct {
+	values  []string
+	expires time.Time
+}
+
+func newSchemaCache(ttl time.Duration) *schemaCache {
+	return &schemaCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]schemaCacheEntry),
+	}
+}
+
+func (c *schemaCache) get(key string) ([]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists || c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.values, true
+}
+
+func (c *schemaCache) set(key string, values []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = schemaCacheEntry{
+		values:  values,
+		expires: c.now().Add(c.ttl),
+	}
+}
@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+type testResourceSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *testResourceSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
+
+func callResource(t *testing.T, d *Neo4JDatasource, path string) *backend.CallResourceResponse {
+	sender := &testResourceSender{}
+
+	req := &backend.CallResourceRequest{Method: http.MethodGet, Path: path, URL: path}
+	err := d.CallResource(context.Background(), req, sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sender.res
+}
+
+func TestSchemaResourcesAreServedFromCache(t *testing.T) {
+	// without driver only cached schema information can be served
+	d := &Neo4JDatasource{schemaCache: newSchemaCache(time.Minute)}
+	d.resourceHandler = newResourceHandler(d)
+
+	d.schemaCache.set("/labels", []string{"Movie", "Person"})
+	d.schemaCache.set("/labels/Known Person/properties", []string{"born", "name"})
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"labels", []string{"Movie", "Person"}},
+		{"labels/Known%20Person/properties", []string{"born", "name"}},
+	}
+
+	for _, test := range tests {
+		res := callResource(t, d, test.path)
+		if res.Status != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, but was %d", test.path, res.Status)
+		}
+
+		var values []string
+		err := json.Unmarshal(res.Body, &values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diff := cmp.Diff(values, test.expected)
+		if diff != "" {
+			t.Error(test.path + ": " + diff)
+		}
+	}
+}
+
+func TestUnknownSchemaResource(t *testing.T) {
+	d := &Neo4JDatasource{schemaCache: newSchemaCache(time.Minute)}
+	d.resourceHandler = newResourceHandler(d)
+
+	for _, path := range []string{"unknown", "labels/Person", "labels/Person/properties/name"} {
+		res := callResource(t, d, path)
+		if res.Status != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, but was %d", path, res.Status)
+		}
+	}
+}
+
+func TestSchemaCacheExpires(t *testing.T) {
+	now := time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC)
+	cache := newSchemaCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("/labels", []string{"Movie"})
+
+	if _, exists := cache.get("/labels"); !exists {
+		t.Fatal("Expected cached labels")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, exists := cache.get("/labels"); exists {
+		t.Fatal("Expected expired labels")
+	}
+}
+
+func TestSchemaResourcesInNeo4J(t *testing.T) {
+	skipIfIsShort(t)
+
+	settings := backend.DataSourceInstanceSettings{}
+	settings.JSONData = asJsonBytes(t, neo4JSettings{
+		Url:      "neo4j://localhost:7687",
+		Username: "neo4j",
+		Password: "Password123",
+	})
+
+	instance, err := NewNeo4JDatasource(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := instance.(*Neo4JDatasource)
+	defer d.Dispose()
+
+	res := callResource(t, d, "labels/Movie/properties")
+	if res.Status != http.StatusOK {
+		t.Fatalf("Expected status 200, but was %d: %s", res.Status, string(res.Body))
+	}
+
+	var values []string
+	err = json.Unmarshal(res.Body, &values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(values, []string{"released", "tagline", "title"})
+	if diff != "" {
+		t.Error(diff)
+	}
+}
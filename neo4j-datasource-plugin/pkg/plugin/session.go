@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// identity of the grafana user, for whom a session is created
+type sessionIdentity struct {
+	// forwarded Authorization header of the user, only set if oauthPassThru is enabled
+	authorization string
+	user          *backend.User
+}
+
+// creates a read session for the identity of the request
+func (d *Neo4JDatasource) newSession(ctx context.Context, identity sessionIdentity) (neo4j.SessionWithContext, error) {
+	return d.newDatabaseSession(ctx, identity, d.settings.Database)
+}
+
+// creates a read session on the given database, e.g. the system database for administration commands
+func (d *Neo4JDatasource) newDatabaseSession(ctx context.Context, identity sessionIdentity, database string) (neo4j.SessionWithContext, error) {
+	config := neo4j.SessionConfig{DatabaseName: database, AccessMode: neo4j.AccessModeRead}
+	if d.settings.DriverLogLevel == DRIVER_LOG_LEVEL_TRACE {
+		config.BoltLogger = &boltLogger{datasourceId: d.id}
+	}
+
+	// session auth replaces the auth of the driver, requires Neo4j 5.5 or newer
+	if d.settings.OAuthPassThru {
+		token, err := bearerToken(identity.authorization)
+		if err != nil {
+			return nil, err
+		}
+		auth := neo4j.BearerAuth(token)
+		config.Auth = &auth
+	}
+
+	if d.settings.Impersonation {
+		user, err := d.settings.impersonatedUser(identity.user)
+		if err != nil {
+			return nil, err
+		}
+		config.ImpersonatedUser = user
+	}
+
+	return d.driver.NewSession(ctx, config), nil
+}
+
+// maps the grafana user to the neo4j user, which is impersonated.
+// A mapping of the login takes precedence over a mapping of the role,
+// without mapping the login is used as neo4j user, unless a mapping is required.
+func (s neo4JSettings) impersonatedUser(user *backend.User) (string, error) {
+	if user == nil || user.Login == "" {
+		return "", errors.New("Impersonation is enabled, but the request has no Grafana user")
+	}
+
+	roleMapping := ""
+	for _, mapping := range s.ImpersonationMapping {
+		if mapping.GrafanaUser == user.Login {
+			return mapping.Neo4jUser, nil
+		}
+		if roleMapping == "" && user.Role != "" && mapping.GrafanaUser == IMPERSONATION_ROLE_PREFIX+user.Role {
+			roleMapping = mapping.Neo4jUser
+		}
+	}
+
+	if roleMapping != "" {
+		return roleMapping, nil
+	}
+	if s.ImpersonationRequireMapping {
+		return "", fmt.Errorf("Impersonation requires a mapping, but the Grafana user %s is not mapped to a Neo4j user", user.Login)
+	}
+	return user.Login, nil
+}
+
+// extracts the token of an Authorization header like 'Bearer <token>'
+func bearerToken(authorization string) (string, error) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	token = strings.TrimSpace(token)
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", errors.New("Forward OAuth Identity is enabled, but the request contains no OAuth token. Sign in to Grafana with OAuth to use this datasource.")
+	}
+	return token, nil
+}
+
+// schema information depends on the privileges of the user, if sessions are created per user
+func (d *Neo4JDatasource) schemaCacheKey(identity sessionIdentity, key string) string {
+	if (d.settings.OAuthPassThru || d.settings.Impersonation) && identity.user != nil {
+		return identity.user.Login + ":" + key
+	}
+	return key
+}
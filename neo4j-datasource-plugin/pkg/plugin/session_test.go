@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		authorization string
+		expected      string
+	}{
+		{"Bearer abc", "abc"},
+		{"bearer  abc ", "abc"},
+		{"", ""},
+		{"Basic abc", ""},
+		{"Bearer ", ""},
+	}
+
+	for _, test := range tests {
+		token, err := bearerToken(test.authorization)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("Expected error for '%s', but was token %s", test.authorization, token)
+			}
+			continue
+		}
+
+		if err != nil || token != test.expected {
+			t.Errorf("Expected token %s for '%s', but was %s %v", test.expected, test.authorization, token, err)
+		}
+	}
+}
+
+func TestQueryDataWithoutForwardedOAuthToken(t *testing.T) {
+	neo4JDatasource := &Neo4JDatasource{
+		settings:   neo4JSettings{OAuthPassThru: true},
+		querySlots: make(chan struct{}, 1),
+	}
+
+	req := &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(`{"cypherQuery": "return 1"}`)}},
+	}
+
+	res, err := neo4JDatasource.QueryData(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resErr := res.Responses["A"].Error
+	if resErr == nil || !strings.Contains(resErr.Error(), "contains no OAuth token") {
+		t.Error("Expected error about missing OAuth token, but was", resErr)
+	}
+}
+
+func TestSchemaCacheKeyPerUser(t *testing.T) {
+	identity := sessionIdentity{user: &backend.User{Login: "alice"}}
+
+	d := &Neo4JDatasource{settings: neo4JSettings{}}
+	if key := d.schemaCacheKey(identity, "/labels"); key != "/labels" {
+		t.Error("Expected shared cache key, but was", key)
+	}
+
+	d.settings.OAuthPassThru = true
+	if key := d.schemaCacheKey(identity, "/labels"); key != "alice:/labels" {
+		t.Error("Expected cache key per user, but was", key)
+	}
+}
+
+func TestImpersonatedUser(t *testing.T) {
+	settings := neo4JSettings{
+		Impersonation: true,
+		ImpersonationMapping: []impersonationMapping{
+			{GrafanaUser: "role:Viewer", Neo4jUser: "viewer"},
+			{GrafanaUser: "alice", Neo4jUser: "alice_db"},
+		},
+	}
+
+	tests := []struct {
+		user     *backend.User
+		expected string
+	}{
+		{&backend.User{Login: "alice", Role: "Viewer"}, "alice_db"},
+		{&backend.User{Login: "bob", Role: "Viewer"}, "viewer"},
+		{&backend.User{Login: "carol", Role: "Editor"}, "carol"},
+	}
+
+	for _, test := range tests {
+		user, err := settings.impersonatedUser(test.user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user != test.expected {
+			t.Errorf("Expected impersonated user %s for %s, but was %s", test.expected, test.user.Login, user)
+		}
+	}
+
+	_, err := settings.impersonatedUser(nil)
+	if err == nil || !strings.Contains(err.Error(), "no Grafana user") {
+		t.Error("Expected error without grafana user, but was", err)
+	}
+}
+
+func TestImpersonatedUserRequiresMapping(t *testing.T) {
+	settings := neo4JSettings{
+		Impersonation:               true,
+		ImpersonationRequireMapping: true,
+		ImpersonationMapping: []impersonationMapping{
+			{GrafanaUser: "role:Viewer", Neo4jUser: "viewer"},
+		},
+	}
+
+	user, err := settings.impersonatedUser(&backend.User{Login: "bob", Role: "Viewer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "viewer" {
+		t.Errorf("Expected impersonated user viewer, but was %s", user)
+	}
+
+	_, err = settings.impersonatedUser(&backend.User{Login: "admin", Role: "Admin"})
+	if err == nil || !strings.Contains(err.Error(), "not mapped") {
+		t.Error("Expected error for user without mapping, but was", err)
+	}
+}
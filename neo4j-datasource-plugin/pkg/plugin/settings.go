@@ -0,0 +1,192 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+)
+
+type neo4JSettings struct {
+	Url      string `json:"url"`
+	Database string `json:"database"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// AuthType selects the authentication, see AUTH_TYPE_*. Custom auth uses
+	// username and password as principal and credentials.
+	AuthType       string `json:"authType"`
+	AuthRealm      string `json:"authRealm"`
+	AuthScheme     string `json:"authScheme"`
+	AuthParameters string `json:"authParameters"`
+
+	// OAuthPassThru forwards the OAuth token of the grafana user as bearer auth of the session.
+	OAuthPassThru bool `json:"oauthPassThru"`
+
+	// Impersonation executes the queries as the neo4j user of the grafana user.
+	Impersonation        bool                   `json:"impersonation"`
+	ImpersonationMapping []impersonationMapping `json:"impersonationMapping"`
+	// ImpersonationRequireMapping rejects users without mapping instead of impersonating their login.
+	ImpersonationRequireMapping bool `json:"impersonationRequireMapping"`
+
+	// secrets of bearer and kerberos auth, which are stored in the secure json data.
+	BearerToken    string `json:"bearerToken"`
+	KerberosTicket string `json:"kerberosTicket"`
+
+	// MaxRows is the default maximum number of records per query, 0 means unlimited.
+	MaxRows int `json:"maxRows"`
+
+	// QueryTimeout is the default timeout of a query, e.g. 30s. Empty means the server side default is used.
+	QueryTimeout string `json:"queryTimeout"`
+
+	// StrictReadOnly rejects all queries, which are not read only according to the planner.
+	StrictReadOnly bool `json:"strictReadOnly"`
+
+	// glob patterns of procedure and function names, e.g. apoc.load.*, which are allowed or denied.
+	// An empty allow list allows all names, which are not denied.
+	ProcedureAllowList []string `json:"procedureAllowList"`
+	ProcedureDenyList  []string `json:"procedureDenyList"`
+
+	// MaxRetryTime limits the time of retries of queries with retry enabled, e.g. 30s.
+	MaxRetryTime string `json:"maxRetryTime"`
+
+	// MaxConcurrentQueries limits the queries executed at the same time by this datasource.
+	MaxConcurrentQueries int `json:"maxConcurrentQueries"`
+
+	// tuning of the connection pool of the driver, durations like 30s or 5m.
+	// Settings which are not configured keep the defaults of the driver.
+	MaxConnectionPoolSize          int    `json:"maxConnectionPoolSize"`
+	MaxConnectionLifetime          string `json:"maxConnectionLifetime"`
+	ConnectionAcquisitionTimeout   string `json:"connectionAcquisitionTimeout"`
+	ConnectionLivenessCheckTimeout string `json:"connectionLivenessCheckTimeout"`
+	SocketConnectTimeout           string `json:"socketConnectTimeout"`
+	SocketKeepalive                *bool  `json:"socketKeepalive"`
+
+	// FetchSize is the number of records pulled from the server in each batch, -1 pulls all records at once.
+	FetchSize int `json:"fetchSize"`
+
+	// DriverLogLevel of the driver logs, which are written to the grafana log, see DRIVER_LOG_LEVEL_*.
+	DriverLogLevel string `json:"driverLogLevel"`
+
+	// PEM encoded certificates and key, which are stored in the secure json data.
+	TlsCACert     string `json:"tlsCACert"`
+	TlsClientCert string `json:"tlsClientCert"`
+	TlsClientKey  string `json:"tlsClientKey"`
+
+	// TlsSkipVerify disables the verification of the server certificate.
+	TlsSkipVerify bool `json:"tlsSkipVerify"`
+
+	// TlsServerName is used to verify the server certificate instead of the host of the url.
+	TlsServerName string `json:"tlsServerName"`
+}
+
+// maps a grafana login, or a role like role:Viewer, to a neo4j user
+type impersonationMapping struct {
+	GrafanaUser string `json:"grafanaUser"`
+	Neo4jUser   string `json:"neo4jUser"`
+}
+
+// prefix of grafana users in the impersonation mapping, which map a role of the organization
+const IMPERSONATION_ROLE_PREFIX string = "role:"
+
+// used if no limit of concurrent queries is configured
+const DEFAULT_MAX_CONCURRENT_QUERIES int = 5
+
+func (s neo4JSettings) maxConcurrentQueries() int {
+	if s.MaxConcurrentQueries > 0 {
+		return s.MaxConcurrentQueries
+	}
+	return DEFAULT_MAX_CONCURRENT_QUERIES
+}
+
+func unmarshalDataSourceSettings(dSIset backend.DataSourceInstanceSettings) (neo4JSettings, error) {
+	// Unmarshal the JSON into our settings Model.
+	var neo4JSettings neo4JSettings
+	err := json.Unmarshal(dSIset.JSONData, &neo4JSettings)
+	if err != nil {
+		return neo4JSettings, err
+
+	}
+
+	if decryptedPassword, exists := dSIset.DecryptedSecureJSONData["password"]; exists {
+		neo4JSettings.Password = decryptedPassword
+	}
+
+	neo4JSettings.BearerToken = dSIset.DecryptedSecureJSONData["bearerToken"]
+	neo4JSettings.KerberosTicket = dSIset.DecryptedSecureJSONData["kerberosTicket"]
+	neo4JSettings.TlsCACert = dSIset.DecryptedSecureJSONData["tlsCACert"]
+	neo4JSettings.TlsClientCert = dSIset.DecryptedSecureJSONData["tlsClientCert"]
+	neo4JSettings.TlsClientKey = dSIset.DecryptedSecureJSONData["tlsClientKey"]
+
+	return neo4JSettings, nil
+}
+
+// checks the settings, which are not validated by the driver itself
+func (s neo4JSettings) validate() error {
+	if s.MaxRows < 0 {
+		return fmt.Errorf("Invalid setting maxRows: must not be negative")
+	}
+
+	if s.MaxConcurrentQueries < 0 {
+		return fmt.Errorf("Invalid setting maxConcurrentQueries: must not be negative")
+	}
+
+	_, err := parseDurationSetting("queryTimeout", s.QueryTimeout)
+	if err != nil {
+		return err
+	}
+
+	for _, mapping := range s.ImpersonationMapping {
+		if mapping.GrafanaUser == "" || mapping.Neo4jUser == "" {
+			return fmt.Errorf("Invalid setting impersonationMapping: grafana user and neo4j user are required")
+		}
+	}
+
+	err = validatePatterns("procedureAllowList", s.ProcedureAllowList)
+	if err != nil {
+		return err
+	}
+
+	err = validatePatterns("procedureDenyList", s.ProcedureDenyList)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.authToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.driverUrl()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.driverConfig("")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parses a duration like 30s or 5m, an empty value is 0
+func parseDurationSetting(name string, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := gtime.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid setting %s: %w", name, err)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("Invalid setting %s: must not be negative", name)
+	}
+	return d, nil
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	tests := []struct {
+		settingsTimeout string
+		queryTimeout    string
+		expected        time.Duration
+	}{
+		{"", "", 0},
+		{"30s", "", 30 * time.Second},
+		{"30s", "2m", 2 * time.Minute},
+		{"", "500ms", 500 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		d := &Neo4JDatasource{settings: neo4JSettings{QueryTimeout: test.settingsTimeout}}
+
+		timeout, err := d.queryTimeout(neo4JQuery{QueryTimeout: test.queryTimeout})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if timeout != test.expected {
+			t.Errorf("Expected timeout %s, but was %s", test.expected, timeout)
+		}
+	}
+}
+
+func TestTransactionConfigWithoutTimeout(t *testing.T) {
+	d := &Neo4JDatasource{settings: neo4JSettings{}}
+
+	txConfig, err := d.transactionConfig(neo4JQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(txConfig) != 0 {
+		t.Error("Expected no transaction config, because a timeout of 0 disables the server side timeout")
+	}
+}
+
+func TestInvalidSettings(t *testing.T) {
+	tests := []struct {
+		settings        neo4JSettings
+		expectedMessage string
+	}{
+		{neo4JSettings{QueryTimeout: "soon"}, "Invalid setting queryTimeout"},
+		{neo4JSettings{QueryTimeout: "-5s"}, "Invalid setting queryTimeout"},
+		{neo4JSettings{MaxRows: -1}, "Invalid setting maxRows"},
+		{neo4JSettings{ImpersonationMapping: []impersonationMapping{{GrafanaUser: "alice"}}}, "Invalid setting impersonationMapping"},
+		{neo4JSettings{ProcedureDenyList: []string{"apoc.[load"}}, "Invalid setting procedureDenyList"},
+		{neo4JSettings{ProcedureAllowList: []string{" "}}, "Invalid setting procedureAllowList"},
+	}
+
+	for _, test := range tests {
+		err := test.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error containing %s, but was %v", test.expectedMessage, err)
+		}
+	}
+}
+
+func TestQueryTimeoutIsEnforced(t *testing.T) {
+	skipIfIsShort(t)
+
+	query := neo4JQuery{
+		CypherQuery:  "UNWIND range(1, 100000000) as i with i where i % 2 = 0 return count(i)",
+		Format:       "table",
+		QueryTimeout: "100ms",
+	}
+
+	settings := neo4JSettings{
+		Url:      "neo4j://localhost:7687",
+		Username: "neo4j",
+		Password: "Password123",
+	}
+
+	_, err := runNeo4JIntegrationQueryWithSettings(t, settings, query)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Error("Expected timeout error, but was", err)
+	}
+}
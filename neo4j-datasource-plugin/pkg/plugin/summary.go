@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// neo4j specific values of the result summary, which are shown in the query inspector
+type neo4JFrameMeta struct {
+	ServerAddress string `json:"serverAddress,omitempty"`
+	ServerAgent   string `json:"serverAgent,omitempty"`
+	Database      string `json:"database,omitempty"`
+	QueryType     string `json:"queryType,omitempty"`
+}
+
+var statementTypeNames = map[neo4j.StatementType]string{
+	neo4j.StatementTypeReadOnly:    "read",
+	neo4j.StatementTypeReadWrite:   "read-write",
+	neo4j.StatementTypeWriteOnly:   "write",
+	neo4j.StatementTypeSchemaWrite: "schema-write",
+}
+
+// adds timing, counters and server of the result summary to the meta of the frames
+func setResultSummary(frames data.Frames, summary neo4j.ResultSummary) {
+	if summary == nil {
+		return
+	}
+
+	custom := neo4JFrameMeta{QueryType: statementTypeNames[summary.StatementType()]}
+	if server := summary.Server(); server != nil {
+		custom.ServerAddress = server.Address()
+		custom.ServerAgent = server.Agent()
+	}
+	if database := summary.Database(); database != nil {
+		custom.Database = database.Name()
+	}
+
+	stats := []data.QueryStat{
+		queryStat("Result available after", "ms", float64(summary.ResultAvailableAfter().Milliseconds())),
+		queryStat("Result consumed after", "ms", float64(summary.ResultConsumedAfter().Milliseconds())),
+	}
+	stats = append(stats, counterStats(summary.Counters())...)
+
+	for _, frame := range frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.Custom = custom
+		frame.Meta.Stats = append(frame.Meta.Stats, stats...)
+	}
+}
+
+// only counters which are not 0 are returned
+func counterStats(counters neo4j.Counters) []data.QueryStat {
+	if counters == nil {
+		return nil
+	}
+
+	values := []struct {
+		name  string
+		value int
+	}{
+		{"Nodes created", counters.NodesCreated()},
+		{"Nodes deleted", counters.NodesDeleted()},
+		{"Relationships created", counters.RelationshipsCreated()},
+		{"Relationships deleted", counters.RelationshipsDeleted()},
+		{"Properties set", counters.PropertiesSet()},
+		{"Labels added", counters.LabelsAdded()},
+		{"Labels removed", counters.LabelsRemoved()},
+		{"Indexes added", counters.IndexesAdded()},
+		{"Indexes removed", counters.IndexesRemoved()},
+		{"Constraints added", counters.ConstraintsAdded()},
+		{"Constraints removed", counters.ConstraintsRemoved()},
+		{"System updates", counters.SystemUpdates()},
+	}
+
+	var stats []data.QueryStat
+	for _, v := range values {
+		if v.value != 0 {
+			stats = append(stats, queryStat(v.name, "", float64(v.value)))
+		}
+	}
+	return stats
+}
+
+func queryStat(name string, unit string, value float64) data.QueryStat {
+	return data.QueryStat{
+		FieldConfig: data.FieldConfig{DisplayName: name, Unit: unit},
+		Value:       value,
+	}
+}
@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type testSummary struct {
+	neo4j.ResultSummary
+}
+
+func (s testSummary) Server() neo4j.ServerInfo           { return testServer{} }
+func (s testSummary) Database() neo4j.DatabaseInfo       { return testDatabase{} }
+func (s testSummary) StatementType() neo4j.StatementType { return neo4j.StatementTypeReadWrite }
+func (s testSummary) Counters() neo4j.Counters           { return testCounters{} }
+func (s testSummary) ResultAvailableAfter() time.Duration {
+	return 3 * time.Millisecond
+}
+func (s testSummary) ResultConsumedAfter() time.Duration {
+	return 12 * time.Millisecond
+}
+
+type testServer struct {
+	neo4j.ServerInfo
+}
+
+func (s testServer) Address() string { return "localhost:7687" }
+func (s testServer) Agent() string   { return "Neo4j/5.12.0" }
+
+type testDatabase struct{}
+
+func (d testDatabase) Name() string { return "neo4j" }
+
+type testCounters struct {
+	neo4j.Counters
+}
+
+func (c testCounters) NodesCreated() int           { return 2 }
+func (c testCounters) NodesDeleted() int           { return 0 }
+func (c testCounters) RelationshipsCreated() int   { return 1 }
+func (c testCounters) RelationshipsDeleted() int   { return 0 }
+func (c testCounters) PropertiesSet() int          { return 0 }
+func (c testCounters) LabelsAdded() int            { return 0 }
+func (c testCounters) LabelsRemoved() int          { return 0 }
+func (c testCounters) IndexesAdded() int           { return 0 }
+func (c testCounters) IndexesRemoved() int         { return 0 }
+func (c testCounters) ConstraintsAdded() int       { return 0 }
+func (c testCounters) ConstraintsRemoved() int     { return 0 }
+func (c testCounters) SystemUpdates() int          { return 0 }
+func (c testCounters) ContainsUpdates() bool       { return true }
+func (c testCounters) ContainsSystemUpdates() bool { return false }
+
+func TestSetResultSummary(t *testing.T) {
+	frames := data.Frames{data.NewFrame("response")}
+	setResultSummary(frames, testSummary{})
+
+	expected := &data.FrameMeta{
+		Custom: neo4JFrameMeta{
+			ServerAddress: "localhost:7687",
+			ServerAgent:   "Neo4j/5.12.0",
+			Database:      "neo4j",
+			QueryType:     "read-write",
+		},
+		Stats: []data.QueryStat{
+			queryStat("Result available after", "ms", 3),
+			queryStat("Result consumed after", "ms", 12),
+			queryStat("Nodes created", "", 2),
+			queryStat("Relationships created", "", 1),
+		},
+	}
+
+	diff := cmp.Diff(frames[0].Meta, expected)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestResultSummaryIntegration(t *testing.T) {
+	skipIfIsShort(t)
+
+	settings := neo4JSettings{
+		Url:      "neo4j://localhost:7687",
+		Username: "neo4j",
+		Password: "Password123",
+	}
+
+	res, err := runNeo4JIntegrationQueryWithSettings(t, settings, neo4JQuery{CypherQuery: "RETURN 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	custom, ok := res.Frames[0].Meta.Custom.(neo4JFrameMeta)
+	if !ok || custom.Database != "neo4j" || custom.QueryType != "read" {
+		t.Error("Expected database and query type in meta, but was", res.Frames[0].Meta.Custom)
+	}
+
+	if len(res.Frames[0].Meta.Stats) != 2 {
+		t.Error("Expected timings in stats, but was", res.Frames[0].Meta.Stats)
+	}
+}
@@ -0,0 +1,189 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// types of frames the time series format can return
+const (
+	TIME_SERIES_TYPE_WIDE  string = "wide"
+	TIME_SERIES_TYPE_MULTI string = "multi"
+)
+
+// Return time series response for time series panels and alerting.
+// The first temporal column is used as time, string and boolean columns as labels
+// and all other columns as values.
+func toTimeSeriesResponse(ctx context.Context, result neo4j.ResultWithContext, limit *rowLimit, timeSeriesType string) (backend.DataResponse, error) {
+	response, err := toDataResponse(ctx, result, limit)
+	if err != nil {
+		return response, err
+	}
+
+	frames, err := toTimeSeriesFrames(response.Frames[0], timeSeriesType)
+	if err != nil {
+		return response, newValidationError(err)
+	}
+
+	response.Frames = frames
+	return response, nil
+}
+
+func toTimeSeriesFrames(frame *data.Frame, timeSeriesType string) (data.Frames, error) {
+	if frame.Rows() == 0 {
+		return data.Frames{frame}, nil
+	}
+
+	longFrame, err := toSortedTimeFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	if longFrame.TimeSeriesSchema().Type == data.TimeSeriesTypeNot {
+		return nil, errors.New("Time series format requires at least one column with numeric values")
+	}
+
+	switch timeSeriesType {
+	case TIME_SERIES_TYPE_MULTI:
+		return toMultiFrames(longFrame), nil
+	case TIME_SERIES_TYPE_WIDE, "":
+		wideFrame, err := toWideFrame(longFrame)
+		if err != nil {
+			return nil, err
+		}
+		return data.Frames{wideFrame}, nil
+	default:
+		return nil, errors.New("Unknown time series type '" + timeSeriesType + "'")
+	}
+}
+
+// creates a copy of the frame with a non nullable time column, which is sorted ascending.
+// Rows without time are dropped and missing labels are replaced by empty strings.
+func toSortedTimeFrame(frame *data.Frame) (*data.Frame, error) {
+	timeIndices := frame.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)
+	if len(timeIndices) == 0 {
+		return nil, errors.New("Time series format requires a column with temporal values")
+	}
+	timeIndex := timeIndices[0]
+	timeField := frame.Fields[timeIndex]
+
+	var rows []int
+	for i := 0; i < timeField.Len(); i++ {
+		if t, ok := timeField.ConcreteAt(i); ok && t != nil {
+			rows = append(rows, i)
+		}
+	}
+
+	timeAt := func(row int) time.Time {
+		t, _ := timeField.ConcreteAt(row)
+		return t.(time.Time)
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		return timeAt(rows[a]).Before(timeAt(rows[b]))
+	})
+
+	sorted := data.NewFrame(frame.Name)
+	sorted.Meta = frame.Meta
+	for i, field := range frame.Fields {
+		var f *data.Field
+		if i == timeIndex {
+			f = data.NewField(field.Name, nil, make([]time.Time, 0, len(rows)))
+		} else {
+			f = data.NewFieldFromFieldType(field.Type(), 0)
+			f.Name = field.Name
+		}
+
+		for _, row := range rows {
+			switch {
+			case i == timeIndex:
+				f.Append(timeAt(row))
+			case field.Type() == data.FieldTypeNullableString && field.At(row).(*string) == nil:
+				empty := ""
+				f.Append(&empty)
+			default:
+				f.Append(field.At(row))
+			}
+		}
+		sorted.Fields = append(sorted.Fields, f)
+	}
+
+	return sorted, nil
+}
+
+func toWideFrame(longFrame *data.Frame) (*data.Frame, error) {
+	if longFrame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		return data.LongToWide(longFrame, nil)
+	}
+
+	if longFrame.Meta == nil {
+		longFrame.Meta = &data.FrameMeta{}
+	}
+	longFrame.Meta.Type = data.FrameTypeTimeSeriesWide
+	return longFrame, nil
+}
+
+// splits a long frame into one frame per value column and label combination
+func toMultiFrames(longFrame *data.Frame) data.Frames {
+	schema := longFrame.TimeSeriesSchema()
+	timeField := longFrame.Fields[schema.TimeIndex]
+
+	var frames data.Frames
+	seriesByKey := make(map[string]*data.Frame)
+
+	for row := 0; row < longFrame.Rows(); row++ {
+		labels := data.Labels{}
+		for _, factorIndex := range schema.FactorIndices {
+			factorField := longFrame.Fields[factorIndex]
+			labels[factorField.Name] = toLabelValue(factorField, row)
+		}
+
+		for _, valueIndex := range schema.ValueIndices {
+			valueField := longFrame.Fields[valueIndex]
+			key := valueField.Name + labels.String()
+
+			series, exists := seriesByKey[key]
+			if !exists {
+				value := data.NewFieldFromFieldType(valueField.Type(), 0)
+				value.Name = valueField.Name
+				value.Labels = labels.Copy()
+
+				series = data.NewFrame(valueField.Name, data.NewField(timeField.Name, nil, []time.Time{}), value)
+				series.Meta = &data.FrameMeta{
+					Type:        data.FrameTypeTimeSeriesMulti,
+					TypeVersion: data.FrameTypeVersion{0, 1},
+				}
+				seriesByKey[key] = series
+				frames = append(frames, series)
+			}
+
+			series.AppendRow(timeField.At(row), valueField.At(row))
+		}
+	}
+
+	return frames
+}
+
+func toLabelValue(field *data.Field, row int) string {
+	val, ok := field.ConcreteAt(row)
+	if !ok {
+		return ""
+	}
+	switch t := val.(type) {
+	case string:
+		return t
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	default:
+		return ""
+	}
+}
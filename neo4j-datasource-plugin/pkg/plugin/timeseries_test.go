@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func ts(minute int) time.Time {
+	return time.Date(2023, 8, 1, 10, minute, 0, 0, time.UTC)
+}
+
+// long result as returned by: RETURN t, host, value
+func longTestFrame() *data.Frame {
+	return data.NewFrame("response",
+		data.NewField("t", nil, []*time.Time{
+			ptrT(ts(1)), ptrT(ts(0)), ptrT(ts(1)), nil, ptrT(ts(0)),
+		}),
+		data.NewField("host", nil, []*string{
+			ptrS("b"), ptrS("a"), ptrS("a"), ptrS("a"), ptrS("b"),
+		}),
+		data.NewField("value", nil, []*int64{
+			ptrI(4), ptrI(1), ptrI(2), ptrI(99), ptrI(3),
+		}),
+	)
+}
+
+func TestTimeSeriesWideFromLong(t *testing.T) {
+	frames, err := toTimeSeriesFrames(longTestFrame(), "wide")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := data.NewFrame("response",
+		data.NewField("t", nil, []time.Time{ts(0), ts(1)}),
+		data.NewField("value", data.Labels{"host": "a"}, []*int64{ptrI(1), ptrI(2)}),
+		data.NewField("value", data.Labels{"host": "b"}, []*int64{ptrI(3), ptrI(4)}),
+	)
+	expected.SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesWide})
+
+	if len(frames) != 1 {
+		t.Fatal("Frames len is not 1")
+	}
+
+	diff := cmp.Diff(frames[0], expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestTimeSeriesMultiFromLong(t *testing.T) {
+	frames, err := toTimeSeriesFrames(longTestFrame(), "multi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+	expected := data.Frames{
+		data.NewFrame("value",
+			data.NewField("t", nil, []time.Time{ts(0), ts(1)}),
+			data.NewField("value", data.Labels{"host": "a"}, []*int64{ptrI(1), ptrI(2)}),
+		).SetMeta(meta),
+		data.NewFrame("value",
+			data.NewField("t", nil, []time.Time{ts(0), ts(1)}),
+			data.NewField("value", data.Labels{"host": "b"}, []*int64{ptrI(3), ptrI(4)}),
+		).SetMeta(meta),
+	}
+
+	diff := cmp.Diff(frames, expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestTimeSeriesAlreadyWide(t *testing.T) {
+	frame := data.NewFrame("response",
+		data.NewField("t", nil, []*time.Time{ptrT(ts(1)), ptrT(ts(0))}),
+		data.NewField("cpu", nil, []*float64{ptrF(0.5), ptrF(0.25)}),
+	)
+
+	frames, err := toTimeSeriesFrames(frame, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := data.NewFrame("response",
+		data.NewField("t", nil, []time.Time{ts(0), ts(1)}),
+		data.NewField("cpu", nil, []*float64{ptrF(0.25), ptrF(0.5)}),
+	)
+	expected.SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesWide})
+
+	diff := cmp.Diff(frames[0], expected, data.FrameTestCompareOptions()...)
+	if diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestTimeSeriesWithoutTimeOrValue(t *testing.T) {
+	withoutTime := data.NewFrame("response",
+		data.NewField("value", nil, []*int64{ptrI(1)}),
+	)
+	withoutValue := data.NewFrame("response",
+		data.NewField("t", nil, []*time.Time{ptrT(ts(0))}),
+		data.NewField("host", nil, []*string{ptrS("a")}),
+	)
+
+	_, err := toTimeSeriesFrames(withoutTime, "wide")
+	if err == nil || !strings.Contains(err.Error(), "temporal values") {
+		t.Error("Expected error about missing time column, but was", err)
+	}
+
+	_, err = toTimeSeriesFrames(withoutValue, "wide")
+	if err == nil || !strings.Contains(err.Error(), "numeric values") {
+		t.Error("Expected error about missing value column, but was", err)
+	}
+}
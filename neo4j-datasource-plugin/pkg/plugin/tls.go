@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// url schemes of the driver which use TLS
+var encryptedSchemes = map[string]string{
+	"bolt+s":    "bolt+ssc",
+	"bolt+ssc":  "bolt+ssc",
+	"neo4j+s":   "neo4j+ssc",
+	"neo4j+ssc": "neo4j+ssc",
+}
+
+// true if any TLS setting is configured
+func (s neo4JSettings) tlsConfigured() bool {
+	return s.TlsCACert != "" || s.TlsClientCert != "" || s.TlsClientKey != "" || s.TlsSkipVerify || s.TlsServerName != ""
+}
+
+// returns the url which is passed to the driver.
+// The driver derives the verification of the server certificate from the url scheme,
+// therefore skip verify and a custom server name switch to the +ssc scheme.
+// The server name is then verified by the tls config itself.
+func (s neo4JSettings) driverUrl() (string, error) {
+	if !s.tlsConfigured() {
+		return s.Url, nil
+	}
+
+	parsed, err := url.Parse(s.Url)
+	if err != nil {
+		return "", fmt.Errorf("Invalid setting url: %w", err)
+	}
+
+	sscScheme, encrypted := encryptedSchemes[parsed.Scheme]
+	if !encrypted {
+		return "", fmt.Errorf("Invalid setting url: TLS settings require an encrypted scheme like neo4j+s or bolt+s, but was '%s'", parsed.Scheme)
+	}
+
+	if s.TlsSkipVerify || s.TlsServerName != "" {
+		parsed.Scheme = sscScheme
+	}
+	return parsed.String(), nil
+}
+
+// builds the tls config for the driver from the PEM encoded certificates of the settings.
+// Returns nil if the defaults of the driver can be used.
+func (s neo4JSettings) tlsConfig() (*tls.Config, error) {
+	if s.TlsCACert == "" && s.TlsClientCert == "" && s.TlsClientKey == "" && s.TlsServerName == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.TlsCACert != "" {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM([]byte(s.TlsCACert)) {
+			return nil, errors.New("Invalid setting tlsCACert: no PEM encoded certificate found")
+		}
+	}
+
+	if s.TlsClientCert != "" || s.TlsClientKey != "" {
+		if s.TlsClientCert == "" || s.TlsClientKey == "" {
+			return nil, errors.New("Invalid setting tlsClientCert: client certificate and key must be configured together")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(s.TlsClientCert), []byte(s.TlsClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid setting tlsClientCert: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	// the driver skips the verification for the +ssc scheme, the certificate
+	// chain is verified here against the configured server name instead of the host
+	if s.TlsServerName != "" && !s.TlsSkipVerify {
+		config.VerifyConnection = verifyServerName(s.TlsServerName, config.RootCAs)
+	}
+
+	return config, nil
+}
+
+func verifyServerName(serverName string, roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server did not provide a certificate")
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			DNSName:       serverName,
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
+// describes errors of the TLS handshake, which the driver only returns as text
+func describeTlsError(err error) (string, bool) {
+	msg := err.Error()
+	if !strings.Contains(msg, "x509:") && !strings.Contains(msg, "tls:") && !strings.Contains(msg, "check that TLS is enabled") {
+		return "", false
+	}
+	return "TLS handshake failed: " + msg + ". Check the CA certificate, client certificate and server name of the datasource.", true
+}
@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// creates a certificate for the dns name, which is self signed if no parent is given
+func createTestCert(t *testing.T, dnsName string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: dnsName},
+		DNSNames:              []string{dnsName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+func TestDriverUrl(t *testing.T) {
+	tests := []struct {
+		settings neo4JSettings
+		expected string
+	}{
+		{neo4JSettings{Url: "neo4j://localhost:7687"}, "neo4j://localhost:7687"},
+		{neo4JSettings{Url: "neo4j+s://localhost:7687", TlsCACert: "ca"}, "neo4j+s://localhost:7687"},
+		{neo4JSettings{Url: "neo4j+s://localhost:7687", TlsSkipVerify: true}, "neo4j+ssc://localhost:7687"},
+		{neo4JSettings{Url: "bolt+s://localhost:7687", TlsServerName: "neo4j.internal"}, "bolt+ssc://localhost:7687"},
+	}
+
+	for _, test := range tests {
+		url, err := test.settings.driverUrl()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if url != test.expected {
+			t.Errorf("Expected url %s, but was %s", test.expected, url)
+		}
+	}
+}
+
+func TestInvalidTlsSettings(t *testing.T) {
+	ca := createTestCert(t, "ca", nil)
+
+	tests := []struct {
+		settings        neo4JSettings
+		expectedMessage string
+	}{
+		{neo4JSettings{Url: "neo4j://localhost:7687", TlsSkipVerify: true}, "Invalid setting url"},
+		{neo4JSettings{Url: "neo4j+s://localhost:7687", TlsCACert: "no certificate"}, "Invalid setting tlsCACert"},
+		{neo4JSettings{Url: "neo4j+s://localhost:7687", TlsClientCert: ca.certPEM}, "Invalid setting tlsClientCert"},
+		{neo4JSettings{Url: "neo4j+s://localhost:7687", TlsClientCert: ca.certPEM, TlsClientKey: "no key"}, "Invalid setting tlsClientCert"},
+	}
+
+	for _, test := range tests {
+		err := test.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), test.expectedMessage) {
+			t.Errorf("Expected error '%s', but was %v", test.expectedMessage, err)
+		}
+	}
+}
+
+func TestTlsConfig(t *testing.T) {
+	ca := createTestCert(t, "ca", nil)
+	client := createTestCert(t, "client", ca)
+
+	settings := neo4JSettings{
+		Url:           "neo4j+s://localhost:7687",
+		TlsCACert:     ca.certPEM,
+		TlsClientCert: client.certPEM,
+		TlsClientKey:  client.keyPEM,
+	}
+
+	config, err := settings.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.RootCAs == nil || len(config.Certificates) != 1 {
+		t.Fatal("Expected custom root CA and client certificate")
+	}
+
+	if config.VerifyConnection != nil {
+		t.Error("Expected the driver to verify the server certificate, if no server name is configured")
+	}
+
+	config, err = neo4JSettings{}.tlsConfig()
+	if err != nil || config != nil {
+		t.Error("Expected no tls config without TLS settings, but was", config, err)
+	}
+}
+
+func TestTlsConfigVerifiesServerName(t *testing.T) {
+	ca := createTestCert(t, "ca", nil)
+	server := createTestCert(t, "neo4j.internal", ca)
+
+	settings := neo4JSettings{Url: "neo4j+s://10.0.0.1:7687", TlsCACert: ca.certPEM, TlsServerName: "neo4j.internal"}
+	config, err := settings.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.VerifyConnection(tls.ConnectionState{PeerCertificates: []*x509.Certificate{server.cert, ca.cert}})
+	if err != nil {
+		t.Error("Expected valid certificate, but was", err)
+	}
+
+	settings.TlsServerName = "other.internal"
+	config, err = settings.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.VerifyConnection(tls.ConnectionState{PeerCertificates: []*x509.Certificate{server.cert, ca.cert}})
+	if err == nil {
+		t.Error("Expected error because of wrong server name")
+	}
+}
@@ -0,0 +1,62 @@
+import React, { ChangeEvent, PureComponent } from 'react';
+import { Button, InlineFieldRow, InlineFormLabel, Input } from '@grafana/ui';
+import { ImpersonationMapping } from './types';
+
+interface Props {
+  mapping?: ImpersonationMapping[];
+  onChange: (mapping: ImpersonationMapping[]) => void;
+}
+
+export class ImpersonationMappingEditor extends PureComponent<Props> {
+  entries = (): ImpersonationMapping[] => {
+    return [...(this.props.mapping || [])];
+  };
+
+  onGrafanaUserChange = (index: number) => (event: ChangeEvent<HTMLInputElement>) => {
+    const entries = this.entries();
+    entries[index] = { ...entries[index], grafanaUser: event.target.value };
+    this.props.onChange(entries);
+  };
+
+  onNeo4jUserChange = (index: number) => (event: ChangeEvent<HTMLInputElement>) => {
+    const entries = this.entries();
+    entries[index] = { ...entries[index], neo4jUser: event.target.value };
+    this.props.onChange(entries);
+  };
+
+  onRemove = (index: number) => () => {
+    const entries = this.entries();
+    entries.splice(index, 1);
+    this.props.onChange(entries);
+  };
+
+  onAdd = () => {
+    const entries = this.entries();
+    entries.push({ grafanaUser: '', neo4jUser: '' });
+    this.props.onChange(entries);
+  };
+
+  render() {
+    return (
+      <div>
+        {this.entries().map((entry, index) => (
+          <InlineFieldRow key={index}>
+            <InlineFormLabel width={8}>Grafana User</InlineFormLabel>
+            <Input
+              width={24}
+              value={entry.grafanaUser}
+              placeholder="login or role:Viewer"
+              onChange={this.onGrafanaUserChange(index)}
+            />
+            <InlineFormLabel width={8}>Neo4j User</InlineFormLabel>
+            <Input width={24} value={entry.neo4jUser} placeholder="user" onChange={this.onNeo4jUserChange(index)} />
+            <Button variant="secondary" icon="trash-alt" aria-label="Remove mapping" onClick={this.onRemove(index)} />
+          </InlineFieldRow>
+        ))}
+        <Button variant="secondary" icon="plus" size="sm" onClick={this.onAdd}>
+          Add mapping
+        </Button>
+      </div>
+    );
+  }
+}
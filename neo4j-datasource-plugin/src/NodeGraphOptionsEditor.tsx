@@ -0,0 +1,174 @@
+import React, { ChangeEvent, PureComponent } from 'react';
+import { Button, InlineFieldRow, InlineFormLabel, Input } from '@grafana/ui';
+import { NodeGraphArc, NodeGraphLabelRule, NodeGraphOptions } from './types';
+
+interface Props {
+  options?: NodeGraphOptions;
+  onChange: (options: NodeGraphOptions) => void;
+  onBlur: () => void;
+}
+
+type SourceOption =
+  | 'nodeTitle'
+  | 'nodeSubTitle'
+  | 'nodeMainStat'
+  | 'nodeSecondaryStat'
+  | 'nodeColor'
+  | 'nodeRadius'
+  | 'edgeMainStat'
+  | 'edgeSecondaryStat';
+
+export class NodeGraphOptionsEditor extends PureComponent<Props> {
+  options = (): NodeGraphOptions => {
+    return this.props.options || {};
+  };
+
+  onSourceChange = (name: SourceOption) => (event: ChangeEvent<HTMLInputElement>) => {
+    this.props.onChange({ ...this.options(), [name]: event.target.value || undefined });
+  };
+
+  arcs = (): NodeGraphArc[] => {
+    return [...(this.options().nodeArcs || [])];
+  };
+
+  onArcChange = (index: number, name: keyof NodeGraphArc) => (event: ChangeEvent<HTMLInputElement>) => {
+    const arcs = this.arcs();
+    arcs[index] = { ...arcs[index], [name]: event.target.value };
+    this.props.onChange({ ...this.options(), nodeArcs: arcs });
+  };
+
+  onRemoveArc = (index: number) => () => {
+    const arcs = this.arcs();
+    arcs.splice(index, 1);
+    this.props.onChange({ ...this.options(), nodeArcs: arcs });
+    this.props.onBlur();
+  };
+
+  onAddArc = () => {
+    const arcs = this.arcs();
+    arcs.push({ property: '', color: '' });
+    this.props.onChange({ ...this.options(), nodeArcs: arcs });
+  };
+
+  rules = (): NodeGraphLabelRule[] => {
+    return [...(this.options().labelRules || [])];
+  };
+
+  onRuleChange = (index: number, name: keyof NodeGraphLabelRule) => (event: ChangeEvent<HTMLInputElement>) => {
+    const rules = this.rules();
+    rules[index] = { ...rules[index], [name]: event.target.value };
+    this.props.onChange({ ...this.options(), labelRules: rules });
+  };
+
+  onRemoveRule = (index: number) => () => {
+    const rules = this.rules();
+    rules.splice(index, 1);
+    this.props.onChange({ ...this.options(), labelRules: rules });
+    this.props.onBlur();
+  };
+
+  onAddRule = () => {
+    const rules = this.rules();
+    rules.push({ label: '' });
+    this.props.onChange({ ...this.options(), labelRules: rules });
+  };
+
+  renderSource = (name: SourceOption, label: string, placeholder: string, tooltip: string) => {
+    return (
+      <>
+        <InlineFormLabel width={8} tooltip={tooltip}>
+          {label}
+        </InlineFormLabel>
+        <Input
+          width={16}
+          value={this.options()[name] || ''}
+          placeholder={placeholder}
+          onChange={this.onSourceChange(name)}
+          onBlur={this.props.onBlur}
+        />
+      </>
+    );
+  };
+
+  render() {
+    const propertyTooltip = 'Name of a property, $label for the first label or $labels for all labels';
+    const edgeTooltip = 'Name of a property or $type for the relationship type';
+
+    return (
+      <div>
+        <InlineFieldRow>
+          {this.renderSource('nodeTitle', 'Node Title', '$label', propertyTooltip)}
+          {this.renderSource('nodeSubTitle', 'Sub Title', 'property', propertyTooltip)}
+          {this.renderSource('nodeMainStat', 'Main Stat', 'property', propertyTooltip)}
+          {this.renderSource('nodeSecondaryStat', 'Second Stat', 'property', propertyTooltip)}
+        </InlineFieldRow>
+        <InlineFieldRow>
+          {this.renderSource('nodeColor', 'Color', 'property', 'Property with a color like red or #ff0000')}
+          {this.renderSource('nodeRadius', 'Radius', 'property', 'Numeric property with the radius of the node')}
+          {this.renderSource('edgeMainStat', 'Edge Stat', '$type', edgeTooltip)}
+          {this.renderSource('edgeSecondaryStat', 'Edge Second', 'property', edgeTooltip)}
+        </InlineFieldRow>
+        {this.arcs().map((arc, index) => (
+          <InlineFieldRow key={'arc' + index}>
+            <InlineFormLabel width={8} tooltip="Numeric property between 0 and 1, shown as section of the circle">
+              Arc
+            </InlineFormLabel>
+            <Input
+              width={16}
+              value={arc.property}
+              placeholder="property"
+              onChange={this.onArcChange(index, 'property')}
+              onBlur={this.props.onBlur}
+            />
+            <InlineFormLabel width={8}>Color</InlineFormLabel>
+            <Input
+              width={16}
+              value={arc.color}
+              placeholder="green"
+              onChange={this.onArcChange(index, 'color')}
+              onBlur={this.props.onBlur}
+            />
+            <Button variant="secondary" icon="trash-alt" aria-label="Remove arc" onClick={this.onRemoveArc(index)} />
+          </InlineFieldRow>
+        ))}
+        {this.rules().map((rule, index) => (
+          <InlineFieldRow key={'rule' + index}>
+            <InlineFormLabel width={8} tooltip="Nodes with the label get the color and icon, the first rule wins">
+              Label
+            </InlineFormLabel>
+            <Input
+              width={16}
+              value={rule.label}
+              placeholder="label"
+              onChange={this.onRuleChange(index, 'label')}
+              onBlur={this.props.onBlur}
+            />
+            <InlineFormLabel width={8}>Color</InlineFormLabel>
+            <Input
+              width={16}
+              value={rule.color || ''}
+              placeholder="red"
+              onChange={this.onRuleChange(index, 'color')}
+              onBlur={this.props.onBlur}
+            />
+            <InlineFormLabel width={8}>Icon</InlineFormLabel>
+            <Input
+              width={16}
+              value={rule.icon || ''}
+              placeholder="database"
+              onChange={this.onRuleChange(index, 'icon')}
+              onBlur={this.props.onBlur}
+            />
+            <Button variant="secondary" icon="trash-alt" aria-label="Remove rule" onClick={this.onRemoveRule(index)} />
+          </InlineFieldRow>
+        ))}
+        <Button variant="secondary" icon="plus" size="sm" onClick={this.onAddArc}>
+          Add arc
+        </Button>{' '}
+        <Button variant="secondary" icon="plus" size="sm" onClick={this.onAddRule}>
+          Add label rule
+        </Button>
+      </div>
+    );
+  }
+}
@@ -0,0 +1,126 @@
+import React, { ChangeEvent, PureComponent } from 'react';
+import { Button, InlineFieldRow, InlineFormLabel, Input, Select } from '@grafana/ui';
+import { SelectableValue } from '@grafana/data';
+import { ParameterType, QueryParameter } from './types';
+
+const ParameterTypes = Object.values(ParameterType).map((type) => ({
+  label: type,
+  value: type,
+})) as Array<SelectableValue<ParameterType>>;
+
+interface Props {
+  parameters?: Record<string, QueryParameter>;
+  onChange: (parameters: Record<string, QueryParameter>) => void;
+}
+
+type Entry = [string, QueryParameter];
+
+interface State {
+  // names which are being edited, but are empty or already used, by index of the entry
+  invalidNames: Record<number, string>;
+}
+
+export class ParametersEditor extends PureComponent<Props, State> {
+  state: State = { invalidNames: {} };
+
+  entries = (): Entry[] => {
+    return Object.entries(this.props.parameters || {});
+  };
+
+  update = (entries: Entry[]) => {
+    this.props.onChange(Object.fromEntries(entries));
+  };
+
+  isNameUsed = (name: string, index: number) => {
+    return this.entries().some(([other], otherIndex) => other === name && otherIndex !== index);
+  };
+
+  // an empty or duplicate name would overwrite or drop a parameter, so it is kept only in the editor
+  onNameChange = (index: number) => (event: ChangeEvent<HTMLInputElement>) => {
+    const name = event.target.value;
+    const invalidNames = { ...this.state.invalidNames };
+    delete invalidNames[index];
+
+    if (name === '' || this.isNameUsed(name, index)) {
+      this.setState({ invalidNames: { ...invalidNames, [index]: name } });
+      return;
+    }
+
+    this.setState({ invalidNames });
+    const entries = this.entries();
+    entries[index] = [name, entries[index][1]];
+    this.update(entries);
+  };
+
+  onNameBlur = (index: number) => () => {
+    const invalidNames = { ...this.state.invalidNames };
+    delete invalidNames[index];
+    this.setState({ invalidNames });
+  };
+
+  onTypeChange = (index: number) => (selected: SelectableValue<ParameterType>) => {
+    const entries = this.entries();
+    entries[index] = [entries[index][0], { ...entries[index][1], type: selected.value || ParameterType.String }];
+    this.update(entries);
+  };
+
+  onValueChange = (index: number) => (event: ChangeEvent<HTMLInputElement>) => {
+    const entries = this.entries();
+    entries[index] = [entries[index][0], { ...entries[index][1], value: event.target.value }];
+    this.update(entries);
+  };
+
+  onRemove = (index: number) => () => {
+    const entries = this.entries();
+    entries.splice(index, 1);
+    this.setState({ invalidNames: {} });
+    this.update(entries);
+  };
+
+  onAdd = () => {
+    const entries = this.entries();
+    let number = entries.length + 1;
+    while (this.isNameUsed('param' + number, -1)) {
+      number++;
+    }
+    entries.push(['param' + number, { type: ParameterType.String, value: '' }]);
+    this.update(entries);
+  };
+
+  render() {
+    return (
+      <div>
+        {this.entries().map(([name, parameter], index) => (
+          <InlineFieldRow key={index}>
+            <InlineFormLabel width={5}>Parameter</InlineFormLabel>
+            <Input
+              width={20}
+              value={this.state.invalidNames[index] ?? name}
+              invalid={index in this.state.invalidNames}
+              title={index in this.state.invalidNames ? 'Name must be unique and not empty' : undefined}
+              placeholder="name"
+              onChange={this.onNameChange(index)}
+              onBlur={this.onNameBlur(index)}
+            />
+            <Select
+              width={14}
+              value={ParameterTypes.find((t) => t.value === parameter.type)}
+              options={ParameterTypes}
+              onChange={this.onTypeChange(index)}
+            />
+            <Input
+              width={40}
+              value={parameter.value}
+              placeholder="value, e.g. $variable"
+              onChange={this.onValueChange(index)}
+            />
+            <Button variant="secondary" icon="trash-alt" aria-label="Remove parameter" onClick={this.onRemove(index)} />
+          </InlineFieldRow>
+        ))}
+        <Button variant="secondary" icon="plus" size="sm" onClick={this.onAdd}>
+          Add parameter
+        </Button>
+      </div>
+    );
+  }
+}
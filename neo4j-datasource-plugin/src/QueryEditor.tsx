@@ -1,8 +1,20 @@
-import React, { PureComponent } from 'react';
-import { CodeEditor, InlineFieldRow, InlineFormLabel, Select } from '@grafana/ui';
+import React, { ChangeEvent, PureComponent } from 'react';
+import { CodeEditor, InlineFieldRow, InlineFormLabel, InlineSwitch, Input, Select } from '@grafana/ui';
 import { QueryEditorProps, SelectableValue } from '@grafana/data';
 import { DataSource } from './datasource';
-import { MyDataSourceOptions, MyQuery, Format } from './types';
+import {
+  MyDataSourceOptions,
+  MyQuery,
+  Format,
+  NodeGraphOptions,
+  NumericType,
+  QueryParameter,
+  QueryType,
+  TimeSeriesType,
+} from './types';
+import { ParametersEditor } from './ParametersEditor';
+import { NodeGraphOptionsEditor } from './NodeGraphOptionsEditor';
+import { registerCypherCompletion } from './completion';
 
 type Props = QueryEditorProps<DataSource, MyQuery, MyDataSourceOptions>;
 
@@ -17,9 +29,77 @@ const Formats = [
     value: Format.NodeGraph,
     description: 'Node Graph View',
   },
+  {
+    label: 'Time Series',
+    value: Format.TimeSeries,
+    description: 'Time Series View, string columns are used as labels',
+  },
+  {
+    label: 'Numeric',
+    value: Format.Numeric,
+    description: 'Numeric values without time for alerting, string columns are used as labels',
+  },
 ] as Array<SelectableValue<Format>>;
 
+const QueryTypes = [
+  {
+    label: 'Query',
+    value: QueryType.Query,
+    description: 'Result of the query',
+  },
+  {
+    label: 'Explain',
+    value: QueryType.Explain,
+    description: 'Plan of the query without executing it',
+  },
+  {
+    label: 'Profile',
+    value: QueryType.Profile,
+    description: 'Plan of the query with statistics of the execution',
+  },
+] as Array<SelectableValue<QueryType>>;
+
+const TimeSeriesTypes = [
+  {
+    label: 'Wide',
+    value: TimeSeriesType.Wide,
+    description: 'One frame with a value field per series',
+  },
+  {
+    label: 'Multi',
+    value: TimeSeriesType.Multi,
+    description: 'One frame per series',
+  },
+] as Array<SelectableValue<TimeSeriesType>>;
+
+const NumericTypes = [
+  {
+    label: 'Long',
+    value: NumericType.Long,
+    description: 'One frame with a row per alert instance',
+  },
+  {
+    label: 'Multi',
+    value: NumericType.Multi,
+    description: 'One frame per alert instance',
+  },
+] as Array<SelectableValue<NumericType>>;
+
 export class QueryEditor extends PureComponent<Props> {
+  completion?: { dispose: () => void };
+
+  componentWillUnmount() {
+    this.completion?.dispose();
+  }
+
+  onEditorDidMount = (editor: any, monaco: any) => {
+    editor.onDidChangeModelContent(() => {
+      this.onCypherQueryChange(editor.getValue());
+    });
+    this.completion?.dispose();
+    this.completion = registerCypherCompletion(monaco, this.props.datasource);
+  };
+
   onCypherQueryChange = (value: string | undefined) => {
     const { onChange, query } = this.props;
     onChange({ ...query, cypherQuery: value || '' });
@@ -31,18 +111,79 @@ export class QueryEditor extends PureComponent<Props> {
     onRunQuery();
   };
 
+  onMaxRowsChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, maxRows: parseInt(event.target.value, 10) || undefined });
+  };
+
+  onQueryTimeoutChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, queryTimeout: event.target.value || undefined });
+  };
+
+  onRetryChange = (event: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, retry: event.currentTarget.checked || undefined });
+    onRunQuery();
+  };
+
+  onParametersChange = (parameters: Record<string, QueryParameter>) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, parameters });
+  };
+
+  onNodeGraphChange = (nodeGraph: NodeGraphOptions) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, nodeGraph });
+  };
+
+  onQueryTypeChanged = (selected: SelectableValue<QueryType>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, queryType: selected.value || QueryType.Query });
+    onRunQuery();
+  };
+
+  resolveQueryType = (value: string | undefined) => {
+    return QueryTypes.find((type) => type.value === value) || QueryTypes[0];
+  };
+
+  onTimeSeriesTypeChanged = (selected: SelectableValue<TimeSeriesType>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, timeSeriesType: selected.value || TimeSeriesType.Wide });
+    onRunQuery();
+  };
+
+  onNumericTypeChanged = (selected: SelectableValue<NumericType>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, numericType: selected.value || NumericType.Long });
+    onRunQuery();
+  };
+
   resolveFormat = (value: string | undefined) => {
-    if (value === Format.NodeGraph) {
-      return Formats[1];
-    }
-    return Formats[0];
+    return Formats.find((format) => format.value === value) || Formats[0];
+  };
+
+  resolveTimeSeriesType = (value: string | undefined) => {
+    return TimeSeriesTypes.find((type) => type.value === value) || TimeSeriesTypes[0];
+  };
+
+  resolveNumericType = (value: string | undefined) => {
+    return NumericTypes.find((type) => type.value === value) || NumericTypes[0];
   };
 
   render() {
     return (
       <div>
-        <CodeEditor height={"240px"} onEditorDidMount={ (editor) => { editor.onDidChangeModelContent (() => {this.onCypherQueryChange(editor.getValue())})}} monacoOptions={{ minimap: {enabled : false}, automaticLayout: true}} value={this.props.query.cypherQuery || ''} language={'cypher'} />
+        <CodeEditor height={"240px"} onEditorDidMount={this.onEditorDidMount} monacoOptions={{ minimap: {enabled : false}, automaticLayout: true}} value={this.props.query.cypherQuery || ''} language={'cypher'} />
         <InlineFieldRow>
+          <InlineFormLabel width={5}>Type</InlineFormLabel>
+          <Select
+            className="width-10"
+            value={this.resolveQueryType(this.props.query.queryType)}
+            options={QueryTypes}
+            onChange={this.onQueryTypeChanged}
+            width="auto"
+          />
           <InlineFormLabel width={5}>Format</InlineFormLabel>
           <Select
             className="width-14"
@@ -52,7 +193,67 @@ export class QueryEditor extends PureComponent<Props> {
             onChange={this.onFormatChanged}
             width="auto"
           />
+          {this.props.query.Format === Format.TimeSeries && (
+            <>
+              <InlineFormLabel width={7}>Series Type</InlineFormLabel>
+              <Select
+                className="width-14"
+                value={this.resolveTimeSeriesType(this.props.query.timeSeriesType)}
+                options={TimeSeriesTypes}
+                onChange={this.onTimeSeriesTypeChanged}
+                width="auto"
+              />
+            </>
+          )}
+          {this.props.query.Format === Format.Numeric && (
+            <>
+              <InlineFormLabel width={7}>Numeric Type</InlineFormLabel>
+              <Select
+                className="width-14"
+                value={this.resolveNumericType(this.props.query.numericType)}
+                options={NumericTypes}
+                onChange={this.onNumericTypeChanged}
+                width="auto"
+              />
+            </>
+          )}
+          <InlineFormLabel width={6} tooltip="Overrides the row limit of the datasource">
+            Row Limit
+          </InlineFormLabel>
+          <Input
+            width={12}
+            type="number"
+            value={this.props.query.maxRows || ''}
+            placeholder="default"
+            onChange={this.onMaxRowsChange}
+            onBlur={this.props.onRunQuery}
+          />
+          <InlineFormLabel width={6} tooltip="Overrides the query timeout of the datasource, e.g. 30s">
+            Timeout
+          </InlineFormLabel>
+          <Input
+            width={12}
+            value={this.props.query.queryTimeout || ''}
+            placeholder="default"
+            onChange={this.onQueryTimeoutChange}
+            onBlur={this.props.onRunQuery}
+          />
+          <InlineFormLabel
+            width={5}
+            tooltip="Retries the query on transient errors within the max retry time of the datasource. Not supported for CALL {} IN TRANSACTIONS."
+          >
+            Retry
+          </InlineFormLabel>
+          <InlineSwitch value={this.props.query.retry || false} onChange={this.onRetryChange} />
         </InlineFieldRow>
+        {this.props.query.Format === Format.NodeGraph && (
+          <NodeGraphOptionsEditor
+            options={this.props.query.nodeGraph}
+            onChange={this.onNodeGraphChange}
+            onBlur={this.props.onRunQuery}
+          />
+        )}
+        <ParametersEditor parameters={this.props.query.parameters} onChange={this.onParametersChange} />
       </div>
     );
   }
@@ -0,0 +1,95 @@
+import { DataSource } from './datasource';
+
+// Minimal subset of the monaco api which is used for the completion
+interface Monaco {
+  languages: {
+    registerCompletionItemProvider: (language: string, provider: any) => { dispose: () => void };
+    CompletionItemKind: { [key: string]: number };
+  };
+}
+
+// Providers are registered globally for the language, therefore each datasource registers one provider,
+// which is shared by all of its editors and disposed with the last one.
+const providers = new Map<string, { editors: number; provider: { dispose: () => void } }>();
+
+// Matches the label of a variable like (n:Person in the query before the cursor
+function findLabel(text: string, variable: string): string | undefined {
+  const pattern = new RegExp('\\(\\s*' + variable + '\\s*:\\s*(\\w+|`[^`]+`)', 'g');
+  let label: string | undefined;
+  for (let match = pattern.exec(text); match; match = pattern.exec(text)) {
+    label = match[1].replace(/^`|`$/g, '');
+  }
+  return label;
+}
+
+/**
+ * Registers completions for labels, relationship types, property keys, procedures and functions
+ * of the database. The schema is loaded from the resource endpoints of the backend.
+ * After a variable like n. the properties of its label are completed, if the label is known.
+ */
+export function registerCypherCompletion(monaco: Monaco, datasource: DataSource) {
+  const registration = providers.get(datasource.uid) || {
+    editors: 0,
+    provider: monaco.languages.registerCompletionItemProvider('cypher', createProvider(monaco, datasource)),
+  };
+  registration.editors++;
+  providers.set(datasource.uid, registration);
+
+  let disposed = false;
+  return {
+    dispose: () => {
+      if (disposed) {
+        return;
+      }
+      disposed = true;
+      registration.editors--;
+      if (registration.editors === 0) {
+        registration.provider.dispose();
+        providers.delete(datasource.uid);
+      }
+    },
+  };
+}
+
+function createProvider(monaco: Monaco, datasource: DataSource) {
+  return {
+    triggerCharacters: [':', '.'],
+    provideCompletionItems: async (model: any, position: any) => {
+      const word = model.getWordUntilPosition(position);
+      const range = {
+        startLineNumber: position.lineNumber,
+        endLineNumber: position.lineNumber,
+        startColumn: word.startColumn,
+        endColumn: word.endColumn,
+      };
+
+      const kinds = monaco.languages.CompletionItemKind;
+      const toItems = (values: string[], kind: number, detail: string) =>
+        values.map((value) => ({ label: value, insertText: value, kind, detail, range }));
+
+      const textBefore: string = model.getValueInRange({
+        startLineNumber: 1,
+        startColumn: 1,
+        endLineNumber: position.lineNumber,
+        endColumn: word.startColumn,
+      });
+      const property = /(\w+)\s*\.$/.exec(textBefore);
+      const label = property ? findLabel(textBefore, property[1]) : undefined;
+      const properties = label ? await datasource.getLabelProperties(label) : [];
+      if (label && properties.length > 0) {
+        return { suggestions: toItems(properties, kinds.Property, label + ' Property') };
+      }
+
+      const schema = await datasource.getSchema();
+      return {
+        suggestions: [
+          ...toItems(schema.labels, kinds.Class, 'Label'),
+          ...toItems(schema.relationshipTypes, kinds.Interface, 'Relationship Type'),
+          ...toItems(schema.propertyKeys, kinds.Property, 'Property Key'),
+          ...toItems(schema.procedures, kinds.Method, 'Procedure'),
+          ...toItems(schema.functions, kinds.Function, 'Function'),
+        ],
+      };
+    },
+  };
+}
@@ -8,11 +8,76 @@ import {
   ScopedVars,
 } from '@grafana/data';
 import { DataSourceWithBackend, getTemplateSrv } from '@grafana/runtime';
-import { MyDataSourceOptions, MyQuery } from './types';
+import { MyDataSourceOptions, MyQuery, ParameterType, QueryParameter, Schema } from './types';
+
+// Parameters which are bound natively as cypher parameters by the backend.
+// They must not be interpolated as text by the template service.
+const BACKEND_PARAMETERS = ['__from', '__to', '__interval_ms', '__interval', '__maxDataPoints'];
+const PARAMETER_PLACEHOLDER = '__neo4jParameter';
+
+// Schema resources are cached by the backend as well, the frontend cache saves the requests on each completion
+const SCHEMA_CACHE_TTL_MS = 5 * 60 * 1000;
+
+// Returns the end of the string literal, quoted name or comment at i, or i if there is none
+function skipLiteral(cypher: string, i: number): number {
+  const c = cypher[i];
+  if (c === "'" || c === '"' || c === '`') {
+    for (let j = i + 1; j < cypher.length; j++) {
+      if (cypher[j] === '\\' && c !== '`') {
+        j++;
+      } else if (cypher[j] === c) {
+        return j + 1;
+      }
+    }
+    return cypher.length;
+  }
+  if (cypher.startsWith('//', i)) {
+    const end = cypher.indexOf('\n', i);
+    return end < 0 ? cypher.length : end;
+  }
+  if (cypher.startsWith('/*', i)) {
+    const end = cypher.indexOf('*/', i + 2);
+    return end < 0 ? cypher.length : end + 2;
+  }
+  return i;
+}
+
+function escapeRegExp(value: string): string {
+  return value.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
+
+// Replaces the cypher parameters with the given names by a placeholder, which the template service ignores.
+// Occurrences in string literals and comments are kept, e.g. '$__from' is still interpolated as text.
+function protectParameters(cypher: string, names: string[]): string {
+  const parameter = new RegExp('^\\$(' + names.map(escapeRegExp).join('|') + ')(?!\\w)');
+  let protectedCypher = '';
+  let i = 0;
+  while (i < cypher.length) {
+    const end = skipLiteral(cypher, i);
+    if (end > i) {
+      protectedCypher += cypher.slice(i, end);
+      i = end;
+      continue;
+    }
+    const match = cypher[i] === '$' ? parameter.exec(cypher.slice(i)) : null;
+    if (match) {
+      protectedCypher += PARAMETER_PLACEHOLDER + match[1];
+      i += match[0].length;
+      continue;
+    }
+    protectedCypher += cypher[i];
+    i++;
+  }
+  return protectedCypher;
+}
 
 export class DataSource extends DataSourceWithBackend<MyQuery, MyDataSourceOptions> {
+  legacyInterpolation: boolean;
+  schemaCache = new Map<string, { expires: number; values: Promise<string[]> }>();
+
   constructor(instanceSettings: DataSourceInstanceSettings<MyDataSourceOptions>) {
     super(instanceSettings);
+    this.legacyInterpolation = instanceSettings.jsonData.legacyInterpolation || false;
   }
 
   /**
@@ -24,13 +89,78 @@ export class DataSource extends DataSourceWithBackend<MyQuery, MyDataSourceOptio
    * Interpolation options: https://grafana.com/docs/grafana/latest/variables/advanced-variable-format-options/
    */
   applyTemplateVariables(query: MyQuery, scopedVars: ScopedVars): Record<string, any> {
-    const evaluatedCypherQuery = getTemplateSrv().replace(query.cypherQuery, scopedVars);
     return {
       ...query,
-      cypherQuery: evaluatedCypherQuery,
+      cypherQuery: this.applyTemplateVariablesToCypher(query, scopedVars),
+      parameters: this.applyTemplateVariablesToParameters(query.parameters, scopedVars),
     };
   }
 
+  // The query text is only interpolated, if legacy interpolation is enabled for backward compatibility.
+  // Parameters bound by the backend and the parameters of the query are never interpolated as text.
+  applyTemplateVariablesToCypher(query: MyQuery, scopedVars: ScopedVars): string {
+    const cypherQuery = query.cypherQuery || '';
+    if (!this.legacyInterpolation) {
+      return cypherQuery;
+    }
+
+    const names = [...BACKEND_PARAMETERS, ...Object.keys(query.parameters || {}).filter((name) => name)];
+    return getTemplateSrv()
+      .replace(protectParameters(cypherQuery, names), scopedVars)
+      .split(PARAMETER_PLACEHOLDER)
+      .join('$');
+  }
+
+  // Dashboard variables are interpolated into the parameter values, which are bound as cypher parameters.
+  applyTemplateVariablesToParameters(
+    parameters: Record<string, QueryParameter> | undefined,
+    scopedVars: ScopedVars
+  ): Record<string, QueryParameter> {
+    const evaluatedParameters: Record<string, QueryParameter> = {};
+    Object.entries(parameters || {}).forEach(([name, parameter]) => {
+      const format = parameter.type === ParameterType.List ? 'json' : undefined;
+      evaluatedParameters[name] = {
+        ...parameter,
+        value: getTemplateSrv().replace(parameter.value, scopedVars, format),
+      };
+    });
+    return evaluatedParameters;
+  }
+
+  // Schema information for the completion
+  async getSchema(): Promise<Schema> {
+    const [labels, relationshipTypes, propertyKeys, procedures, functions] = await Promise.all([
+      this.getSchemaResource('labels'),
+      this.getSchemaResource('relationship-types'),
+      this.getSchemaResource('property-keys'),
+      this.getSchemaResource('procedures'),
+      this.getSchemaResource('functions'),
+    ]);
+    return { labels, relationshipTypes, propertyKeys, procedures, functions };
+  }
+
+  // Properties of all nodes with the given label
+  async getLabelProperties(label: string): Promise<string[]> {
+    return this.getSchemaResource('labels/' + encodeURIComponent(label) + '/properties');
+  }
+
+  // Failed requests are not cached, so they are retried on the next completion
+  getSchemaResource(path: string): Promise<string[]> {
+    const cached = this.schemaCache.get(path);
+    if (cached && cached.expires > Date.now()) {
+      return cached.values;
+    }
+
+    const values: Promise<string[]> = this.getResource(path).catch(() => {
+      if (this.schemaCache.get(path)?.values === values) {
+        this.schemaCache.delete(path);
+      }
+      return [];
+    });
+    this.schemaCache.set(path, { expires: Date.now() + SCHEMA_CACHE_TTL_MS, values });
+    return values;
+  }
+
   // Used for VariableQuery
   async metricFindQuery(query: MyQuery, options: any): Promise<MetricFindValue[]> {
     const evaluatedQuery = this.applyTemplateVariables(query, options.scopedVars);
@@ -1,20 +1,123 @@
 import { DataQuery, DataSourceJsonData } from '@grafana/data';
 
 export interface MyQuery extends DataQuery {
+  queryType?: QueryType;
   cypherQuery: string;
   Format: Format;
+  parameters?: Record<string, QueryParameter>;
+  timeSeriesType?: TimeSeriesType;
+  numericType?: NumericType;
+  maxRows?: number;
+  queryTimeout?: string;
+  retry?: boolean;
+  nodeGraph?: NodeGraphOptions;
+}
+
+// Define ParameterType enum for the types of user defined cypher parameters
+export enum ParameterType {
+  String = 'string',
+  Int = 'int',
+  Float = 'float',
+  Bool = 'bool',
+  List = 'list',
+  DateTime = 'datetime',
+  Duration = 'duration',
+}
+
+// Values are always strings, because they are interpolated with dashboard variables
+export interface QueryParameter {
+  type: ParameterType;
+  value: string;
 }
 
 // Define Format enum for visualization format in the Query Editor
 export enum Format {
   Table = 'table',
   NodeGraph = 'nodegraph',
+  TimeSeries = 'timeseries',
+  Numeric = 'numeric',
+}
+
+// Define QueryType enum, explain and profile return the plan of the query instead of the result
+export enum QueryType {
+  Query = 'query',
+  Explain = 'explain',
+  Profile = 'profile',
+}
+
+// Define TimeSeriesType enum for the frames returned by the time series format
+export enum TimeSeriesType {
+  Wide = 'wide',
+  Multi = 'multi',
+}
+
+// Define NumericType enum for the frames returned by the numeric format
+export enum NumericType {
+  Long = 'long',
+  Multi = 'multi',
+}
+
+// Maps properties or labels ($label, $labels, $type) onto the fields of the node graph
+export interface NodeGraphOptions {
+  nodeTitle?: string;
+  nodeSubTitle?: string;
+  nodeMainStat?: string;
+  nodeSecondaryStat?: string;
+  nodeColor?: string;
+  nodeRadius?: string;
+  nodeArcs?: NodeGraphArc[];
+  labelRules?: NodeGraphLabelRule[];
+  edgeMainStat?: string;
+  edgeSecondaryStat?: string;
+}
+
+export interface NodeGraphArc {
+  property: string;
+  color: string;
+}
+
+export interface NodeGraphLabelRule {
+  label: string;
+  color?: string;
+  icon?: string;
 }
 
 export type FormatInterface = {
   [key in Format]: string;
 };
 
+// Schema information of the database for the completion in the query editor
+export interface Schema {
+  labels: string[];
+  relationshipTypes: string[];
+  propertyKeys: string[];
+  procedures: string[];
+  functions: string[];
+}
+
+export enum AuthType {
+  None = 'none',
+  Basic = 'basic',
+  Bearer = 'bearer',
+  Kerberos = 'kerberos',
+  Custom = 'custom',
+}
+
+export enum DriverLogLevel {
+  Off = 'off',
+  Error = 'error',
+  Warning = 'warning',
+  Info = 'info',
+  Debug = 'debug',
+  Trace = 'trace',
+}
+
+// maps a grafana login, or a role like role:Viewer, to the impersonated neo4j user
+export interface ImpersonationMapping {
+  grafanaUser: string;
+  neo4jUser: string;
+}
+
 /**
  * These are options configured for each DataSource instance
  */
@@ -22,8 +125,39 @@ export interface MyDataSourceOptions extends DataSourceJsonData {
   url: string;
   database?: string;
   username?: string;
+  authType?: AuthType;
+  authRealm?: string;
+  authScheme?: string;
+  authParameters?: string;
+  oauthPassThru?: boolean;
+  impersonation?: boolean;
+  impersonationMapping?: ImpersonationMapping[];
+  impersonationRequireMapping?: boolean;
+  maxRows?: number;
+  queryTimeout?: string;
+  maxConcurrentQueries?: number;
+  maxRetryTime?: string;
+  strictReadOnly?: boolean;
+  procedureAllowList?: string[];
+  procedureDenyList?: string[];
+  legacyInterpolation?: boolean;
+  maxConnectionPoolSize?: number;
+  maxConnectionLifetime?: string;
+  connectionAcquisitionTimeout?: string;
+  connectionLivenessCheckTimeout?: string;
+  socketConnectTimeout?: string;
+  socketKeepalive?: boolean;
+  fetchSize?: number;
+  driverLogLevel?: DriverLogLevel;
+  tlsSkipVerify?: boolean;
+  tlsServerName?: string;
 }
 
 export interface MySecureDataSourceOptions {
   password?: string;
+  bearerToken?: string;
+  kerberosTicket?: string;
+  tlsCACert?: string;
+  tlsClientCert?: string;
+  tlsClientKey?: string;
 }